@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/inventory"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func runDrift(args []string) int {
+	fs := flag.NewFlagSet("drift", flag.ExitOnError)
+	traversal := traversalFlags{}
+	traversal.register(fs)
+	format := fs.String("format", "table", "Output format, one of table, json, csv")
+	maxBehind := fs.Int("max-behind", 0, "Exit with non-zero code if any module revision is more than this number of revisions behind the newest one in the tree")
+	fs.Parse(args)
+
+	level, err := traversal.level()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	results := newOutput(level)
+	defer func() {
+		if s := results.String(); s != "" {
+			fmt.Fprintln(os.Stderr, s)
+		}
+	}()
+
+	report, err := updater.List(context.Background(), pathsOrDefault(fs.Args()), traversal.options())
+	results.report(report)
+	if err != nil {
+		return 1
+	}
+	groups := inventory.Drift(inventory.NewUsages(report.Calls))
+
+	if err := writeDriftGroups(os.Stdout, *format, groups); err != nil {
+		results.fail(err)
+		return 1
+	}
+
+	exceeded := false
+	for _, g := range groups {
+		if g.MaxBehind() > *maxBehind {
+			exceeded = true
+			results.log(updater.LevelError, fmt.Sprintf(
+				"%s: %s is %d revision(s) behind %s, threshold is %d",
+				g.Key, g.Revisions[len(g.Revisions)-1].Revision, g.MaxBehind(), g.Newest, *maxBehind,
+			))
+		}
+	}
+
+	if exceeded || results.hasErrors() {
+		return 1
+	}
+
+	return 0
+}
+
+func writeDriftGroups(w io.Writer, format string, groups []inventory.DriftGroup) error {
+	header := []string{"MODULE", "REVISION", "BEHIND", "FILES"}
+	rows := [][]string{}
+	for _, g := range groups {
+		for _, r := range g.Revisions {
+			rows = append(rows, []string{g.Key, string(r.Revision), strconv.Itoa(r.Behind), strings.Join(r.Files, ",")})
+		}
+	}
+
+	return writeFormatted(w, format, groups, header, rows)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func runFmt(args []string) int {
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+	traversal := traversalFlags{}
+	traversal.register(fs)
+	policyName := fs.String("policy", "git-https", "Canonical form of git sources, one of git-https, git-ssh, minimal")
+	check := fs.Bool("check", false, "Do not write files, exit with non-zero code if any source is not in canonical form")
+	fs.Parse(args)
+
+	level, err := traversal.level()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	results := newOutput(level)
+	defer func() {
+		fmt.Println(results.String())
+	}()
+
+	policy, err := module.PolicyFromString(*policyName)
+	if err != nil {
+		results.fail(err)
+		return 2
+	}
+
+	normalize := func(s module.Source) module.Source {
+		return module.Normalize(s, policy)
+	}
+	strategy := strategies.NewStrictUpdater(normalize).
+		WithCondition(func(s module.Source) bool {
+			return normalize(s).String() != s.String()
+		})
+
+	options := traversal.options()
+	options.Write = !*check
+	report, err := updater.Update(context.Background(), pathsOrDefault(fs.Args()), strategy, options)
+	results.report(report)
+
+	if err != nil || results.hasErrors() {
+		return 1
+	}
+
+	if *check && len(report.Changes) > 0 {
+		results.log(updater.LevelError, fmt.Sprintf("%d module source(s) are not in canonical form", len(report.Changes)))
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/git"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// gitFlags holds flags to commit written files
+type gitFlags struct {
+	Commit bool
+	Group  string
+	Branch string
+	Force  bool
+}
+
+func (f *gitFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.Commit, "git.commit", false, "Commit written files, requires -write flag")
+	fs.StringVar(&f.Group, "git.group", string(git.GroupByRule), "How changes are split into commits, one of rule, repository")
+	fs.StringVar(&f.Branch, "git.branch", "", "Create this branch and commit to it instead of the current one")
+	fs.BoolVar(&f.Force, "git.force", false, "Run even if the working tree has uncommitted changes")
+}
+
+// prepare checks the working tree of paths before any file is written and switches to the new branch, if requested
+func (f *gitFlags) prepare(paths []string) (*git.Repository, error) {
+	if _, err := git.ParseGrouping(f.Group); err != nil {
+		return nil, err
+	}
+
+	repository, err := git.OpenAll(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	dirty, err := repository.IsDirty()
+	if err != nil {
+		return nil, err
+	}
+	if dirty && !f.Force {
+		return nil, errors.New("working tree " + repository.Dir() + " has uncommitted changes, commit or stash them or use -git.force flag")
+	}
+
+	if f.Branch != "" {
+		if err := repository.CreateBranch(f.Branch); err != nil {
+			return nil, err
+		}
+	}
+
+	return repository, nil
+}
+
+// commit makes a commit per group of changes in written files
+func (f *gitFlags) commit(repository *git.Repository, report *updater.Report, results *output) error {
+	groups, err := git.GroupChanges(writtenChanges(report), git.Grouping(f.Group))
+	if err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		message := g.Message(repository.Dir())
+		hash, err := repository.Commit(message, g.Files)
+		if err != nil {
+			return err
+		}
+		results.info("Committed " + hash + ": " + strings.SplitN(message, "\n", 2)[0])
+	}
+
+	return nil
+}
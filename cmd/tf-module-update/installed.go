@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/installed"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func runInstalled(args []string) int {
+	fs := flag.NewFlagSet("installed", flag.ExitOnError)
+	traversal := traversalFlags{}
+	traversal.register(fs)
+	format := fs.String("format", "table", "Output format, one of table, json, csv")
+	dataDir := fs.String("data-dir", installed.DefaultDataDir, "Terraform data folder relative to root module, the same as TF_DATA_DIR")
+	fs.Parse(args)
+
+	level, err := traversal.level()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	results := newOutput(level)
+	defer func() {
+		if s := results.String(); s != "" {
+			fmt.Fprintln(os.Stderr, s)
+		}
+	}()
+
+	paths := pathsOrDefault(fs.Args())
+	report, err := updater.List(context.Background(), paths, traversal.options())
+	results.report(report)
+	if err != nil {
+		return 1
+	}
+
+	findings := []installed.Finding{}
+	for _, dir := range rootModuleDirs(paths, report.Calls) {
+		manifest, err := installed.LoadManifest(dir, *dataDir)
+		if os.IsNotExist(err) {
+			results.debug("modules are not installed in " + dir)
+			continue
+		}
+		if err != nil {
+			results.fail(err)
+			continue
+		}
+
+		findings = append(findings, installed.Compare(dir, report.Calls, manifest)...)
+	}
+
+	if err := writeInstalledFindings(os.Stdout, *format, findings); err != nil {
+		results.fail(err)
+		return 1
+	}
+
+	if len(findings) > 0 || results.hasErrors() {
+		return 1
+	}
+
+	return 0
+}
+
+// rootModuleDirs returns folders with module calls and the given folders, as a root module
+// without calls might still have modules installed
+func rootModuleDirs(paths []string, calls []updater.ModuleCall) []string {
+	seen := map[string]bool{}
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			if abs, err := filepath.Abs(p); err == nil {
+				seen[abs] = true
+			}
+		}
+	}
+	for _, c := range calls {
+		seen[c.Dir()] = true
+	}
+
+	dirs := make([]string, 0, len(seen))
+	for d := range seen {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+
+	return dirs
+}
+
+func writeInstalledFindings(w io.Writer, format string, findings []installed.Finding) error {
+	header := []string{"DIR", "MODULE", "STATUS", "DECLARED", "INSTALLED"}
+	rows := [][]string{}
+	for _, f := range findings {
+		rows = append(rows, []string{f.Dir, f.Key, string(f.Kind), f.Declared, f.Installed})
+	}
+
+	return writeFormatted(w, format, findings, header, rows)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/lint"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// lintEntry is a single lint finding in the output
+type lintEntry struct {
+	File     string `json:"file"`
+	Name     string `json:"name"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Source   string `json:"source"`
+}
+
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	traversal := traversalFlags{}
+	traversal.register(fs)
+	format := fs.String("format", "table", "Output format, one of table, json, csv")
+	fix := fs.Bool("fix", false, "Fix violations which have deterministic fix and write files before reporting the rest")
+	configPath := fs.String("config", "", "Path to lint configuration file. Defaults to "+lint.DefaultConfigFile+" in the current directory, if it exists")
+	fs.Parse(args)
+
+	level, err := traversal.level()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	results := newOutput(level)
+	defer func() {
+		if s := results.String(); s != "" {
+			fmt.Fprintln(os.Stderr, s)
+		}
+	}()
+
+	config, err := loadLintConfig(*configPath)
+	if err != nil {
+		results.fail(err)
+		return 2
+	}
+
+	paths := pathsOrDefault(fs.Args())
+	linter := lint.NewLinter(config)
+
+	if *fix {
+		options := traversal.options()
+		options.Write = true
+		report, err := updater.Update(context.Background(), paths, lint.NewFixStrategy(linter), options)
+		results.report(report)
+		if err != nil {
+			return 1
+		}
+	}
+
+	report, err := updater.List(context.Background(), paths, traversal.options())
+	results.report(report)
+	if err != nil {
+		return 1
+	}
+	findings := linter.Lint(report.Calls)
+
+	if err := writeLintFindings(os.Stdout, *format, findings); err != nil {
+		results.fail(err)
+		return 1
+	}
+
+	for _, f := range findings {
+		if f.Severity >= updater.LevelError {
+			return 1
+		}
+	}
+
+	if results.hasErrors() {
+		return 1
+	}
+
+	return 0
+}
+
+// loadLintConfig loads configuration from the given path or from the default file, if it exists
+func loadLintConfig(path string) (lint.Config, error) {
+	if path != "" {
+		return lint.LoadConfig(path)
+	}
+
+	if _, err := os.Stat(lint.DefaultConfigFile); err == nil {
+		return lint.LoadConfig(lint.DefaultConfigFile)
+	}
+
+	return lint.Config{}, nil
+}
+
+func writeLintFindings(w io.Writer, format string, findings []lint.Finding) error {
+	header := []string{"FILE", "NAME", "RULE", "SEVERITY", "MESSAGE", "SOURCE"}
+	entries := []lintEntry{}
+	rows := [][]string{}
+	for _, f := range findings {
+		entry := lintEntry{
+			File:     f.Call.File,
+			Name:     f.Call.Name,
+			Rule:     f.RuleID,
+			Severity: f.Severity.String(),
+			Message:  f.Message,
+			Source:   f.Call.Source,
+		}
+		entries = append(entries, entry)
+		rows = append(rows, []string{entry.File, entry.Name, entry.Rule, entry.Severity, entry.Message, entry.Source})
+	}
+
+	return writeFormatted(w, format, entries, header, rows)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// listEntry is a single module call in the inventory
+type listEntry struct {
+	File       string      `json:"file"`
+	Name       string      `json:"name"`
+	Block      string      `json:"block"`
+	Kind       module.Kind `json:"kind"`
+	Source     string      `json:"source"`
+	Repository string      `json:"repository"`
+	Scheme     string      `json:"scheme"`
+	Host       string      `json:"host"`
+	Module     string      `json:"module"`
+	Submodule  string      `json:"submodule"`
+	Revision   string      `json:"revision"`
+}
+
+// repositoryGroup holds number of module calls per revision of a single repository
+type repositoryGroup struct {
+	Repository string          `json:"repository"`
+	Kind       module.Kind     `json:"kind"`
+	Calls      int             `json:"calls"`
+	Revisions  []revisionCount `json:"revisions"`
+}
+
+type revisionCount struct {
+	Revision string `json:"revision"`
+	Count    int    `json:"count"`
+}
+
+func runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	traversal := traversalFlags{}
+	traversal.register(fs)
+	format := fs.String("format", "table", "Output format, one of table, json, csv")
+	group := fs.Bool("group", false, "Group module calls by repository and count calls per revision")
+	fs.Parse(args)
+
+	level, err := traversal.level()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	results := newOutput(level)
+	defer func() {
+		if s := results.String(); s != "" {
+			fmt.Fprintln(os.Stderr, s)
+		}
+	}()
+
+	report, err := updater.List(context.Background(), pathsOrDefault(fs.Args()), traversal.options())
+	results.report(report)
+	if err != nil {
+		return 1
+	}
+	entries := newListEntries(report.Calls)
+
+	if *group {
+		err = writeRepositoryGroups(os.Stdout, *format, groupByRepository(entries))
+	} else {
+		err = writeListEntries(os.Stdout, *format, entries)
+	}
+	if err != nil {
+		results.fail(err)
+	}
+
+	if results.hasErrors() {
+		return 1
+	}
+
+	return 0
+}
+
+func newListEntries(calls []updater.ModuleCall) []listEntry {
+	entries := make([]listEntry, 0, len(calls))
+	for _, c := range calls {
+		entry := listEntry{
+			File:   c.File,
+			Name:   c.Name,
+			Block:  c.Block,
+			Kind:   module.KindOther,
+			Source: c.Source,
+		}
+
+		if source, err := module.ParseSource(c.Source); err == nil {
+			entry.Kind = source.Kind()
+			entry.Repository = source.Repository()
+			entry.Scheme = source.Scheme
+			entry.Host = source.Host
+			entry.Module = source.Module
+			entry.Submodule = source.Submodule
+			entry.Revision = string(source.Revision)
+		}
+
+		// registry modules keep their version in a separate attribute
+		if entry.Revision == "" {
+			entry.Revision = c.Version
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func groupByRepository(entries []listEntry) []repositoryGroup {
+	groups := []repositoryGroup{}
+	index := map[string]int{}
+	for _, e := range entries {
+		i, ok := index[e.Repository]
+		if !ok {
+			i = len(groups)
+			index[e.Repository] = i
+			groups = append(groups, repositoryGroup{Repository: e.Repository, Kind: e.Kind})
+		}
+
+		groups[i].Calls++
+		found := false
+		for r := range groups[i].Revisions {
+			if groups[i].Revisions[r].Revision == e.Revision {
+				groups[i].Revisions[r].Count++
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups[i].Revisions = append(groups[i].Revisions, revisionCount{Revision: e.Revision, Count: 1})
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Repository < groups[j].Repository
+	})
+	for _, g := range groups {
+		sort.Slice(g.Revisions, func(i, j int) bool {
+			return g.Revisions[i].Revision < g.Revisions[j].Revision
+		})
+	}
+
+	return groups
+}
+
+func writeListEntries(w io.Writer, format string, entries []listEntry) error {
+	header := []string{"FILE", "NAME", "KIND", "SCHEME", "HOST", "MODULE", "SUBMODULE", "REVISION"}
+	rows := [][]string{}
+	for _, e := range entries {
+		rows = append(rows, []string{e.File, e.Name, string(e.Kind), e.Scheme, e.Host, e.Module, e.Submodule, e.Revision})
+	}
+
+	return writeFormatted(w, format, entries, header, rows)
+}
+
+func writeRepositoryGroups(w io.Writer, format string, groups []repositoryGroup) error {
+	header := []string{"REPOSITORY", "KIND", "CALLS", "REVISION", "COUNT"}
+	rows := [][]string{}
+	for _, g := range groups {
+		for _, r := range g.Revisions {
+			rows = append(rows, []string{g.Repository, string(g.Kind), strconv.Itoa(g.Calls), r.Revision, strconv.Itoa(r.Count)})
+		}
+	}
+
+	return writeFormatted(w, format, groups, header, rows)
+}
+
+// writeFormatted renders data as JSON or the rows as table or CSV
+func writeFormatted(w io.Writer, format string, data interface{}, header []string, rows [][]string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	case "table":
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, row := range append([][]string{header}, rows...) {
+			for i, cell := range row {
+				if i > 0 {
+					fmt.Fprint(writer, "\t")
+				}
+				fmt.Fprint(writer, cell)
+			}
+			fmt.Fprintln(writer)
+		}
+		return writer.Flush()
+	}
+
+	return fmt.Errorf("unknown output format: %s", format)
+}
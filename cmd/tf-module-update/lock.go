@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/lockfile"
+	"github.com/maxim-nazarenko/tf-module-update/internal/mirror"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// lockFlags holds flags shared by lock and verify commands
+type lockFlags struct {
+	traversal traversalFlags
+	File      string
+	MirrorDir string
+}
+
+func (f *lockFlags) register(fs *flag.FlagSet) {
+	f.traversal.register(fs)
+	fs.StringVar(&f.File, "lock-file", lockfile.DefaultFile, "Path to the lock file, paths of module calls are recorded relative to its folder")
+	fs.StringVar(&f.MirrorDir, "mirror.dir", "", "Folder with local clones of module repositories laid out as <host>/<path>, e.g. github.com/org/modules.git, not needed for file:// sources")
+}
+
+// listCalls collects module calls to lock or verify
+func (f *lockFlags) listCalls(paths []string, results *output) ([]updater.ModuleCall, error) {
+	report, err := updater.List(context.Background(), pathsOrDefault(paths), f.traversal.options())
+	results.report(report)
+
+	return report.Calls, err
+}
+
+func runLock(args []string) int {
+	fs := flag.NewFlagSet("lock", flag.ExitOnError)
+	config := lockFlags{}
+	config.register(fs)
+	fs.Parse(args)
+
+	level, err := config.traversal.level()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	results := newOutput(level)
+	defer func() {
+		if s := results.String(); s != "" {
+			fmt.Println(s)
+		}
+	}()
+
+	calls, err := config.listCalls(fs.Args(), results)
+	if err != nil {
+		return 1
+	}
+
+	previous, err := lockfile.Load(config.File)
+	if err != nil && !os.IsNotExist(err) {
+		results.fail(err)
+		return 1
+	}
+
+	lock, errs := lockfile.Build(calls, filepath.Dir(config.File), previous, mirror.New(config.MirrorDir))
+	for _, err := range errs {
+		results.fail(err)
+	}
+	if results.hasErrors() {
+		return 1
+	}
+
+	if err := lock.Save(config.File); err != nil {
+		results.fail(err)
+		return 1
+	}
+	results.info(fmt.Sprintf("Locked %d module call(s) in %s", len(lock.Modules), config.File))
+
+	return 0
+}
+
+func runVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	config := lockFlags{}
+	config.register(fs)
+	fs.Parse(args)
+
+	level, err := config.traversal.level()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	results := newOutput(level)
+	defer func() {
+		if s := results.String(); s != "" {
+			fmt.Println(s)
+		}
+	}()
+
+	calls, err := config.listCalls(fs.Args(), results)
+	if err != nil {
+		return 1
+	}
+
+	lock, err := lockfile.Load(config.File)
+	if err != nil {
+		results.fail(err)
+		return 1
+	}
+
+	for _, err := range lockfile.Verify(calls, filepath.Dir(config.File), lock, mirror.New(config.MirrorDir)) {
+		results.fail(err)
+	}
+	if results.hasErrors() {
+		return 1
+	}
+	results.info("Module sources match " + config.File)
+
+	return 0
+}
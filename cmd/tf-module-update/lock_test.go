@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/lockfile"
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestLockMixedSources(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	repository := testhelpers.NewGitRepository(t, "vpc/main.tf", "")
+	testhelpers.RunGit(t, repository, "tag", "v1.0.0")
+	vpc := "git::file://" + filepath.ToSlash(repository) + "//vpc?ref=v1.0.0"
+
+	dir := t.TempDir()
+	assert.NoError(os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`module "vpc" { source = "`+vpc+`" }
+module "dns" { source = "s3::https://s3-eu-west-1.amazonaws.com/example-org/dns.zip" }
+module "eks" { source = "git@github.com:example-org/modules.git//eks?ref=v1.0.0" }
+module "db" {
+  source  = "terraform-aws-modules/rds/aws"
+  version = "6.1.0"
+}
+`), 0644))
+	lockFile := filepath.Join(dir, lockfile.DefaultFile)
+
+	assert.Equal(0, runLock([]string{"-lock-file", lockFile, dir}))
+	lock, err := lockfile.Load(lockFile)
+	assert.NoError(err)
+	assert.Equal(1, len(lock.Modules))
+	assert.Equal("vpc", lock.Modules[0].Name)
+
+	assert.Equal(0, runVerify([]string{"-lock-file", lockFile, dir}))
+
+	assert.NoError(os.MkdirAll(filepath.Join(dir, ".terraform", "modules"), 0755))
+	assert.NoError(os.WriteFile(filepath.Join(dir, ".terraform", "modules", "modules.json"), []byte(`{"Modules": [
+  {"Key": "", "Source": "", "Dir": "."},
+  {"Key": "vpc", "Source": "`+vpc+`", "Dir": ".terraform/modules/vpc/vpc"},
+  {"Key": "dns", "Source": "s3::https://s3-eu-west-1.amazonaws.com/example-org/dns.zip", "Dir": ".terraform/modules/dns"},
+  {"Key": "eks", "Source": "git@github.com:example-org/modules.git//eks?ref=v1.0.0", "Dir": ".terraform/modules/eks/eks"},
+  {"Key": "db", "Source": "registry.terraform.io/terraform-aws-modules/rds/aws", "Version": "6.1.0", "Dir": ".terraform/modules/db"}
+]}`), 0644))
+
+	assert.Equal(0, runInstalled([]string{dir}))
+}
+
+func TestLockRunModules(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	repository := testhelpers.NewGitRepository(t, "vpc/main.tf", "", "dns/main.tf", "")
+	testhelpers.RunGit(t, repository, "tag", "v1.0.0")
+	source := func(submodule string) string {
+		return "git::file://" + filepath.ToSlash(repository) + "//" + submodule + "?ref=v1.0.0"
+	}
+
+	dir := t.TempDir()
+	assert.NoError(os.WriteFile(filepath.Join(dir, "main.tftest.hcl"), []byte(`run "vpc" {
+  module { source = "`+source("vpc")+`" }
+}
+run "dns" {
+  module { source = "`+source("dns")+`" }
+}
+run "plan" {
+  command = plan
+}
+`), 0644))
+	lockFile := filepath.Join(dir, lockfile.DefaultFile)
+
+	assert.Equal(0, runLock([]string{"-lock-file", lockFile, dir}))
+	lock, err := lockfile.Load(lockFile)
+	assert.NoError(err)
+	assert.Equal(2, len(lock.Modules))
+	assert.Equal(source("vpc"), lock.Modules[0].Source)
+	assert.Equal(source("dns"), lock.Modules[1].Source)
+
+	assert.Equal(0, runVerify([]string{"-lock-file", lockFile, dir}))
+}
@@ -1,82 +1,293 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-
-	"github.com/maxim-nazarenko/tf-module-update/internal/conditions"
-	"github.com/maxim-nazarenko/tf-module-update/internal/module"
-	"github.com/maxim-nazarenko/tf-module-update/internal/processing"
-	"github.com/maxim-nazarenko/tf-module-update/internal/processing/logging"
-	"github.com/maxim-nazarenko/tf-module-update/internal/strategies"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/internal/backup"
+	"github.com/maxim-nazarenko/tf-module-update/internal/fileutil"
+	"github.com/maxim-nazarenko/tf-module-update/internal/git"
+	"github.com/maxim-nazarenko/tf-module-update/internal/mirror"
+	"github.com/maxim-nazarenko/tf-module-update/internal/summary"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
 )
 
 type AppConfig struct {
-	Write      bool
-	LogLevel   logging.Level
-	Paths      []string
-	FromSource module.Source
-	ToSource   module.Source
+	Write          bool
+	Interactive    bool
+	Backup         bool
+	BackupManifest string
+	SummaryFile    string
+	ValidateRefs   bool
+	Changelog      bool
+	CheckInterface bool
+	MinAge         time.Duration
+	MirrorDir      string
+	Git            gitFlags
+	StdinFilename  string
+	Traversal      traversalFlags
+	LogLevel       updater.Level
+	Paths          []string
+	FromSource     module.Source
+	ToSource       module.Source
+}
+
+// latestRevision is the value of -to.revision flag which updates sources to the newest release found in the mirror
+const latestRevision = module.Revision("latest")
+
+// stdinPath is the path which makes the command read a file from stdin and write it to stdout
+const stdinPath = "-"
+
+// commands maps subcommand names to their entry points,
+// updating of module sources is the default command when no subcommand is given
+var commands = map[string]func(args []string) int{
+	"list":      runList,
+	"drift":     runDrift,
+	"lint":      runLint,
+	"fmt":       runFmt,
+	"restore":   runRestore,
+	"lock":      runLock,
+	"verify":    runVerify,
+	"installed": runInstalled,
 }
 
 // =======================================================
 
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			os.Exit(command(os.Args[2:]))
+		}
+	}
 
 	config, err := parseFlags()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	if len(config.Paths) < 1 {
-		config.Paths = []string{"."}
-	}
+	config.Paths = pathsOrDefault(config.Paths)
 
 	os.Exit(run(config))
 }
 
 func run(config *AppConfig) int {
-	results := processing.NewResults(config.LogLevel)
+	filter := hasStdinPath(config.Paths)
+
+	// in filter mode stdout holds the updated file, so diagnostics go to stderr
+	diagnostics := os.Stdout
+	if filter {
+		diagnostics = os.Stderr
+	}
+
+	results := newOutput(config.LogLevel)
 	defer func() {
-		fmt.Println(results.String())
+		if output := results.String(); !filter || output != "" {
+			fmt.Fprintln(diagnostics, output)
+		}
 	}()
 
-	updateCondition, err := conditionFromSource(config.FromSource)
+	if filter && (len(config.Paths) > 1 || config.Interactive) {
+		results.fail(errors.New("path \"-\" cannot be combined with other paths or -interactive flag"))
+		return 1
+	}
+
+	updateCondition, err := conditions.FromSource(config.FromSource)
 	if err != nil {
-		results.Append(err)
+		results.fail(err)
 		return 1
 	}
-	results.Append(processing.NewResultFactory().Debug("searching for module sources: " + config.FromSource.String()))
-	results.Append(processing.NewResultFactory().Debug("updating source with: " + config.ToSource.String()))
-	strategy := strategies.NewStrictUpdater(
-		func(s module.Source) module.Source {
-			return s.Merge(config.ToSource)
-		}).
-		WithCondition(updateCondition)
-
-	processing.NewManager(processing.Config{
-		Write:            config.Write,
-		ExcludeItemsFunc: processing.DefaultExclusionFunc,
-	}, strategy).
-		ProcessPaths(config.Paths, results)
-
-	if results.HasErrors() {
+	results.debug("searching for module sources: " + config.FromSource.String())
+	results.debug("updating source with: " + config.ToSource.String())
+	repositories := mirror.New(config.MirrorDir)
+	var strategy strategies.Strategy
+	if config.ToSource.Revision == latestRevision {
+		if config.ToSource != (module.Source{Revision: latestRevision}) {
+			results.fail(errors.New("-to.revision=latest cannot be combined with other -to.* flags"))
+			return 1
+		}
+		strategy = strategies.NewLatestUpdater(repositories, config.MinAge).WithCondition(updateCondition)
+	} else {
+		if config.MinAge != 0 {
+			results.fail(errors.New("-min-age flag requires -to.revision=latest"))
+			return 1
+		}
+		strategy = strategies.NewStrictUpdater(
+			func(s module.Source) module.Source {
+				return s.Merge(config.ToSource)
+			}).
+			WithCondition(updateCondition)
+	}
+
+	if config.Backup && (!(config.Write || config.Interactive) || filter) {
+		results.fail(errors.New("-backup flag requires -write or -interactive flag and cannot be used with \"-\" path"))
+		return 1
+	}
+
+	var repository *git.Repository
+	if config.Git.Commit {
+		if !(config.Write || config.Interactive) || filter {
+			results.fail(errors.New("-git.commit flag requires -write or -interactive flag and cannot be used with \"-\" path"))
+			return 1
+		}
+		repository, err = config.Git.prepare(config.Paths)
+		if err != nil {
+			results.fail(err)
+			return 1
+		}
+	}
+
+	options := config.Traversal.options()
+	options.Write = config.Write || config.Interactive
+	options.Backup = config.Backup
+	if config.ValidateRefs {
+		options.Validator = repositories
+	}
+	if config.Changelog {
+		options.Changelogs = repositories
+	}
+	if config.CheckInterface {
+		options.InterfaceChecker = repositories
+	}
+	if config.Interactive {
+		options.Approver = updater.NewInteractiveApprover(os.Stdin, os.Stdout)
+	}
+
+	var report *updater.Report
+	if filter {
+		report, err = updater.UpdateSource(context.Background(), os.Stdin, os.Stdout, config.StdinFilename, strategy, options)
+	} else {
+		report, err = updater.Update(context.Background(), config.Paths, strategy, options)
+	}
+	results.report(report)
+	if err != nil {
+		return 1
+	}
+
+	if config.Backup && len(report.Written) > 0 {
+		if err := saveBackupManifest(config.BackupManifest, report.Written); err != nil {
+			results.fail(err)
+			return 1
+		}
+		restore := "tf-module-update restore"
+		if config.BackupManifest != backup.DefaultManifestFile {
+			restore += " -manifest=" + config.BackupManifest
+		}
+		results.info(
+			"Original files are kept with " + updater.BackupSuffix + " suffix, run \"" + restore + "\" to roll the changes back",
+		)
+	}
+
+	if config.SummaryFile != "" {
+		if err := writeSummary(config.SummaryFile, report, options.Write); err != nil {
+			results.fail(err)
+			return 1
+		}
+	}
+
+	if repository != nil {
+		if err := config.Git.commit(repository, report, results); err != nil {
+			results.fail(err)
+			return 1
+		}
+	}
+
+	if results.hasErrors() {
 		return 1
 	}
 
 	return 0
 }
 
+// writeSummary writes Markdown summary of the run, file paths are shown relative to the current directory
+// writeSummary writes summary of changes in written files, or of all proposed changes when files are not written
+func writeSummary(path string, report *updater.Report, write bool) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	run := summary.Run{Changes: report.Changes, Skips: report.Skips, Errors: report.Errors, BaseDir: wd, Proposed: !write}
+	if write {
+		run.Changes = writtenChanges(report)
+	}
+
+	return fileutil.WriteFileAtomic(path, []byte(run.Markdown()))
+}
+
+// writtenChanges returns changes of the files which were written, e.g. changes of files
+// modified by somebody else during the run are left out
+func writtenChanges(report *updater.Report) []updater.Change {
+	written := map[string]bool{}
+	for _, w := range report.Written {
+		written[w.File] = true
+	}
+	changes := []updater.Change{}
+	for _, c := range report.Changes {
+		if written[c.Call.File] {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+// parseAge parses duration which might be given in days, e.g. "7d", besides units of time.ParseDuration
+func parseAge(s string) (time.Duration, error) {
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	age, err := time.ParseDuration(s)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+
+	return age, nil
+}
+
+// hasStdinPath reports if any of the paths asks to read the file from stdin
+func hasStdinPath(paths []string) bool {
+	for _, p := range paths {
+		if p == stdinPath {
+			return true
+		}
+	}
+
+	return false
+}
+
 func parseFlags() (*AppConfig, error) {
 	config := AppConfig{}
 
 	flag.BoolVar(&config.Write, "write", false, "Write files. Defaults to printing possible updates without actual writing")
 
-	var logLevel string
-	flag.StringVar(&logLevel, "log.level", "info", "One of trace, debug, info, warn, error")
+	flag.BoolVar(&config.Interactive, "interactive", false, "Ask to approve each change, only accepted changes are written")
+	flag.BoolVar(&config.Backup, "backup", false, "Keep original content of written files with .bak suffix, so the run might be rolled back with restore command")
+	flag.StringVar(&config.BackupManifest, "backup.manifest", backup.DefaultManifestFile, "Path to save the list of written files to with -backup flag, pass it to -manifest flag of restore command")
+	flag.StringVar(&config.SummaryFile, "summary-file", "", "Write Markdown summary of updates to this file, e.g. to use it as pull request description")
+	flag.BoolVar(&config.ValidateRefs, "validate-refs", false, "Check that the new ref and submodule exist in the module repository before writing a source, see -mirror.dir")
+	flag.BoolVar(&config.Changelog, "changelog", false, "Show commit subjects and CHANGELOG.md sections between the old and the new ref of every change, see -mirror.dir")
+	flag.BoolVar(&config.CheckInterface, "check-interface", false, "Compare variables and outputs of the module at the old and the new ref and report breaking changes, see -mirror.dir")
+	var minAge string
+	flag.StringVar(&minAge, "min-age", "", "With -to.revision=latest, take only releases older than this into account, e.g. 7d or 36h")
+	flag.StringVar(&config.MirrorDir, "mirror.dir", "", "Folder with local clones of module repositories laid out as <host>/<path>, e.g. github.com/org/modules.git, not needed for file:// sources")
+	flag.StringVar(&config.StdinFilename, "stdin.filename", "main.tf", "File name used to pick the syntax when \"-\" path reads the file from stdin")
+	config.Traversal.register(flag.CommandLine)
+	config.Git.register(flag.CommandLine)
 
 	var fromURL string
 	var toURL string
@@ -106,11 +317,19 @@ func parseFlags() (*AppConfig, error) {
 	var fromRevisionStr string
 	var toRevisionStr string
 	flag.StringVar(&fromRevisionStr, "from.revision", "", "Filter modules to update by this revision")
-	flag.StringVar(&toRevisionStr, "to.revision", "", "Update matching modules with this new revision")
+	flag.StringVar(&toRevisionStr, "to.revision", "", "Update matching modules with this new revision, \"latest\" picks the newest release from -mirror.dir")
 
 	flag.Parse()
 	// end of flags parsing
 
+	if minAge != "" {
+		age, err := parseAge(minAge)
+		if err != nil {
+			return nil, err
+		}
+		config.MinAge = age
+	}
+
 	fromSource, err := module.ParseSource(fromURL)
 	if err != nil {
 		return nil, err
@@ -163,9 +382,9 @@ func parseFlags() (*AppConfig, error) {
 	config.FromSource = fromSource
 	config.ToSource = toSource
 
-	level, err := processing.LevelFromString(logLevel)
+	level, err := config.Traversal.level()
 	if err != nil {
-		return nil, errors.New("cannot parse log level: " + err.Error())
+		return nil, err
 	}
 
 	config.LogLevel = level
@@ -173,32 +392,3 @@ func parseFlags() (*AppConfig, error) {
 
 	return &config, nil
 }
-
-func conditionFromSource(source module.Source) (conditions.Condition, error) {
-	activeConditions := []conditions.Condition{}
-	if source.Scheme != "" {
-		activeConditions = append(activeConditions, conditions.SchemeMatches(source.Scheme))
-	}
-
-	if source.Host != "" {
-		activeConditions = append(activeConditions, conditions.HostMatches(source.Host))
-	}
-
-	if source.Module != "" {
-		activeConditions = append(activeConditions, conditions.ModuleMatches(source.Module))
-	}
-
-	if source.Submodule != "" {
-		activeConditions = append(activeConditions, conditions.SubmoduleMatches(source.Submodule))
-	}
-
-	if source.Revision != "" {
-		activeConditions = append(activeConditions, conditions.RevisionMatches(source.Revision))
-	}
-
-	if len(activeConditions) == 0 {
-		return conditions.False(), errors.New("no conditions provided")
-	}
-
-	return conditions.All(activeConditions...), nil
-}
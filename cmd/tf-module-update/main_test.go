@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func TestWriteSummary(t *testing.T) {
+	change := func(file string) updater.Change {
+		source := module.Source{SpecialPrefix: "git::", Scheme: "https", Host: "github.com", Module: "/example-org/modules.git", Submodule: "//" + file}
+		c := updater.Change{Call: updater.ModuleCall{File: file + ".tf", Block: "module"}, Before: source, After: source}
+		c.Before.Revision, c.After.Revision = "v1.0.0", "v1.1.0"
+		return c
+	}
+	report := &updater.Report{
+		Changes: []updater.Change{change("vpc"), change("dns")},
+		// dns.tf was modified by somebody else during the run, so it is not written
+		Written: []updater.Written{{File: "vpc.tf"}},
+	}
+
+	testCases := []struct {
+		name           string
+		write          bool
+		expectedHeader string
+		expectedFiles  []string
+	}{
+		{
+			name:           "written files",
+			write:          true,
+			expectedHeader: "## Module updates",
+			expectedFiles:  []string{"vpc.tf"},
+		},
+		{
+			name:           "dry run",
+			expectedHeader: "## Proposed module updates",
+			expectedFiles:  []string{"dns.tf", "vpc.tf"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			path := filepath.Join(t.TempDir(), "summary.md")
+
+			assert.NoError(writeSummary(path, report, tc.write))
+			content, err := os.ReadFile(path)
+			assert.NoError(err)
+			assert.Equal(true, strings.HasPrefix(string(content), tc.expectedHeader+"\n"))
+			files := []string{}
+			for _, line := range strings.Split(string(content), "\n") {
+				if strings.HasPrefix(line, "- ") && strings.HasSuffix(line, ".tf") {
+					files = append(files, strings.TrimPrefix(line, "- "))
+				}
+			}
+			assert.Equal(tc.expectedFiles, files)
+		})
+	}
+}
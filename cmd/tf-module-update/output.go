@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// output collects messages and errors of a command to render them at the end
+type output struct {
+	level    updater.Level
+	messages []updater.Message
+	errors   []error
+}
+
+// log adds the message, it is rendered only if its level is not below the output level
+func (o *output) log(level updater.Level, text string) {
+	o.messages = append(o.messages, updater.Message{Level: level, Text: text})
+}
+
+func (o *output) debug(text string) {
+	o.log(updater.LevelDebug, text)
+}
+
+func (o *output) info(text string) {
+	o.log(updater.LevelInfo, text)
+}
+
+// fail adds the error, errors are always rendered after messages
+func (o *output) fail(err error) {
+	if err != nil {
+		o.errors = append(o.errors, err)
+	}
+}
+
+// report adds messages and errors of the report
+func (o *output) report(r *updater.Report) {
+	o.messages = append(o.messages, r.Messages...)
+	o.errors = append(o.errors, r.Errors...)
+}
+
+// hasErrors reports if any error was added
+func (o *output) hasErrors() bool {
+	return len(o.errors) > 0
+}
+
+// String renders messages of the output level and above followed by errors, one per line
+func (o *output) String() string {
+	lines := []string{}
+	for _, m := range o.messages {
+		if m.Level >= o.level {
+			lines = append(lines, m.Text)
+		}
+	}
+	for _, err := range o.errors {
+		lines = append(lines, err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func newOutput(level updater.Level) *output {
+	return &output{level: level}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/backup"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	manifestPath := fs.String("manifest", backup.DefaultManifestFile, "Path to backup manifest written by the run with -backup flag")
+	force := fs.Bool("force", false, "Restore files even if they were changed after the run")
+	fs.Parse(args)
+
+	results := newOutput(updater.LevelInfo)
+	defer func() {
+		if s := results.String(); s != "" {
+			fmt.Println(s)
+		}
+	}()
+
+	manifest, err := backup.LoadManifest(*manifestPath)
+	if err != nil {
+		results.fail(err)
+		return 1
+	}
+
+	restored, errs := manifest.Restore(*force)
+	for _, e := range restored {
+		results.info("Restored " + e.File)
+	}
+	for _, err := range errs {
+		results.fail(err)
+	}
+	if len(errs) > 0 {
+		// only files left unrestored are kept in the manifest, so they might be restored later with -force
+		isRestored := map[string]bool{}
+		for _, e := range restored {
+			isRestored[e.File] = true
+		}
+		remaining := backup.Manifest{}
+		for _, e := range manifest.Files {
+			if !isRestored[e.File] {
+				remaining.Files = append(remaining.Files, e)
+			}
+		}
+		if err := remaining.Save(*manifestPath); err != nil {
+			results.fail(err)
+		}
+		return 1
+	}
+
+	if err := os.Remove(*manifestPath); err != nil {
+		results.fail(err)
+		return 1
+	}
+
+	return 0
+}
+
+// saveBackupManifest saves list of written files to the path for restore command
+func saveBackupManifest(path string, written []updater.Written) error {
+	manifest := backup.Manifest{}
+	for _, w := range written {
+		manifest.Files = append(manifest.Files, backup.Entry{File: w.File, Backup: w.Backup, SHA256: w.SHA256})
+	}
+
+	return manifest.Save(path)
+}
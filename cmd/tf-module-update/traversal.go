@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"runtime"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// traversalFlags holds flags shared by all commands which walk Terraform files
+type traversalFlags struct {
+	LogLevel      string
+	Jobs          int
+	Terragrunt    bool
+	Include       stringList
+	Exclude       stringList
+	MaxDepth      int
+	NoIgnoreFiles bool
+}
+
+func (f *traversalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.LogLevel, "log.level", "info", "One of trace, debug, info, warn, error")
+	fs.IntVar(&f.Jobs, "jobs", runtime.NumCPU(), "Number of files to process concurrently")
+	fs.BoolVar(&f.Terragrunt, "terragrunt", false, "Process Terragrunt \"terraform { source = ... }\" blocks in *.hcl files")
+	fs.Var(&f.Include, "include", "Process only files matching this glob, e.g. \"envs/prod/**\". Might be repeated")
+	fs.Var(&f.Exclude, "exclude", "Skip files and folders matching this glob, e.g. \"**/examples/**\". Might be repeated")
+	fs.IntVar(&f.MaxDepth, "max-depth", 0, "Walk at most this number of folder levels, 1 means only files of the given folders, 0 means no limit")
+	fs.BoolVar(&f.NoIgnoreFiles, "no-ignore-files", false, "Do not honour .gitignore and .tf-module-update-ignore files")
+}
+
+func (f *traversalFlags) level() (updater.Level, error) {
+	level, err := updater.LevelFromString(f.LogLevel)
+	if err != nil {
+		return level, errors.New("cannot parse log level: " + err.Error())
+	}
+
+	return level, nil
+}
+
+func (f *traversalFlags) options() updater.Options {
+	options := updater.Options{
+		Jobs:        f.Jobs,
+		Terragrunt:  f.Terragrunt,
+		Include:     f.Include,
+		Exclude:     f.Exclude,
+		MaxDepth:    f.MaxDepth,
+		IgnoreFiles: updater.DefaultIgnoreFiles,
+	}
+	if f.NoIgnoreFiles {
+		options.IgnoreFiles = nil
+	}
+
+	return options
+}
+
+// stringList is a flag which might be given several times
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// pathsOrDefault returns paths to process, current directory is used when nothing is given
+func pathsOrDefault(paths []string) []string {
+	if len(paths) < 1 {
+		return []string{"."}
+	}
+
+	return paths
+}
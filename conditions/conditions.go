@@ -1,6 +1,10 @@
 package conditions
 
-import "github.com/maxim-nazarenko/tf-module-update/internal/module"
+import (
+	"errors"
+
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
 
 // All builds a composite condition that requires all conditions to return true
 func All(conditions ...Condition) Condition {
@@ -63,6 +67,50 @@ func SchemeMatches(scheme string) Condition {
 	}
 }
 
+// KindMatches builds condition that returns true if source is of the given kind
+func KindMatches(kind module.Kind) Condition {
+	return func(s module.Source) bool {
+		return s.Kind() == kind
+	}
+}
+
+// Not builds condition that negates the given one
+func Not(condition Condition) Condition {
+	return func(s module.Source) bool {
+		return !condition(s)
+	}
+}
+
+// FromSource builds condition that returns true if source matches all non-empty parts of the given one
+func FromSource(source module.Source) (Condition, error) {
+	activeConditions := []Condition{}
+	if source.Scheme != "" {
+		activeConditions = append(activeConditions, SchemeMatches(source.Scheme))
+	}
+
+	if source.Host != "" {
+		activeConditions = append(activeConditions, HostMatches(source.Host))
+	}
+
+	if source.Module != "" {
+		activeConditions = append(activeConditions, ModuleMatches(source.Module))
+	}
+
+	if source.Submodule != "" {
+		activeConditions = append(activeConditions, SubmoduleMatches(source.Submodule))
+	}
+
+	if source.Revision != "" {
+		activeConditions = append(activeConditions, RevisionMatches(source.Revision))
+	}
+
+	if len(activeConditions) == 0 {
+		return False(), errors.New("no conditions provided")
+	}
+
+	return All(activeConditions...), nil
+}
+
 // False builds condition that always returns false
 func False() Condition {
 	return func(s module.Source) bool {
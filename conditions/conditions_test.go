@@ -3,8 +3,8 @@ package conditions
 import (
 	"testing"
 
-	"github.com/maxim-nazarenko/tf-module-update/internal/module"
 	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
 )
 
 func TestConditionsAll(t *testing.T) {
@@ -196,3 +196,80 @@ func TestRevisionMatches(t *testing.T) {
 		})
 	}
 }
+
+func TestKindMatches(t *testing.T) {
+	testCases := []struct {
+		name           string
+		kind           module.Kind
+		moduleSource   module.Source
+		expectedResult bool
+	}{
+		{
+			name:           "kind matches",
+			kind:           module.KindGit,
+			moduleSource:   module.Source{SpecialPrefix: "git::", Scheme: "https", Host: "example.com", Module: "/example-org/aws/vpc"},
+			expectedResult: true,
+		},
+		{
+			name:           "kind does not match",
+			kind:           module.KindGit,
+			moduleSource:   module.Source{Module: "./modules/vpc"},
+			expectedResult: false,
+		},
+	}
+
+	var result bool
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			result = KindMatches(tc.kind)(tc.moduleSource)
+			assert.Equal(tc.expectedResult, result)
+		})
+	}
+}
+
+func TestNot(t *testing.T) {
+	assert := testhelpers.Assert(t)
+
+	assert.Equal(true, Not(False())(module.Source{}))
+	assert.Equal(false, Not(Not(False()))(module.Source{}))
+}
+
+func TestFromSource(t *testing.T) {
+	testCases := []struct {
+		name           string
+		from           module.Source
+		source         module.Source
+		expectedResult bool
+		expectedError  bool
+	}{
+		{
+			name:           "all parts match",
+			from:           module.Source{Host: "github.com", Revision: "v1"},
+			source:         module.Source{Scheme: "https", Host: "github.com", Module: "org/repo.git", Revision: "v1"},
+			expectedResult: true,
+		},
+		{
+			name:           "one part differs",
+			from:           module.Source{Host: "github.com", Revision: "v1"},
+			source:         module.Source{Host: "github.com", Revision: "v2"},
+			expectedResult: false,
+		},
+		{
+			name:          "empty source",
+			from:          module.Source{},
+			source:        module.Source{},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			condition, err := FromSource(tc.from)
+			assert.Equal(tc.expectedError, err != nil)
+			assert.Equal(tc.expectedResult, condition(tc.source))
+		})
+	}
+}
@@ -1,6 +1,6 @@
 package conditions
 
-import "github.com/maxim-nazarenko/tf-module-update/internal/module"
+import "github.com/maxim-nazarenko/tf-module-update/module"
 
 // Condition is binary function to make decision
 type Condition func(module.Source) bool
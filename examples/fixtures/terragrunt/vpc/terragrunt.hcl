@@ -0,0 +1,12 @@
+include "root" {
+  path = find_in_parent_folders()
+}
+
+terraform {
+  source = "tfr:///terraform-aws-modules/vpc/aws?version=3.3.0"
+}
+
+inputs = {
+  name = "simple-example"
+  cidr = "10.0.0.0/16"
+}
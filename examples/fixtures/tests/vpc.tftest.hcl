@@ -0,0 +1,9 @@
+run "setup" {
+  module {
+    source = "git::https://github.com/username/terraform-modules/azure.git//vpc?ref=v0.2.2"
+  }
+}
+
+run "plan" {
+  command = plan
+}
@@ -0,0 +1,3 @@
+module "aws-vpc" {
+  source = "git::https://github.com/terraform-aws-modules/terraform-aws.git//src/vpc?ref=v2.77.0"
+}
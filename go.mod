@@ -2,4 +2,7 @@ module github.com/maxim-nazarenko/tf-module-update
 
 go 1.16
 
-require github.com/hashicorp/hcl/v2 v2.10.0
+require (
+	github.com/hashicorp/hcl/v2 v2.10.0
+	github.com/zclconf/go-cty v1.8.0
+)
@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/fileutil"
+)
+
+// Save writes the manifest to the given path replacing the previous one
+func (m Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return fileutil.WriteFileAtomic(path, append(data, '\n'))
+}
+
+// Restore writes original content back to every file of the manifest and removes backups
+//
+// Files changed since the run are not restored unless force is set, restored entries
+// and errors of the rest are returned
+func (m Manifest) Restore(force bool) ([]Entry, []error) {
+	restored := []Entry{}
+	errs := []error{}
+	for _, e := range m.Files {
+		if err := e.restore(force); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		restored = append(restored, e)
+	}
+
+	return restored, errs
+}
+
+func (e Entry) restore(force bool) error {
+	if !force {
+		current, err := ioutil.ReadFile(e.File)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(current)
+		if hex.EncodeToString(sum[:]) != e.SHA256 {
+			return fmt.Errorf("%s: file was changed after the run, not restoring it", e.File)
+		}
+	}
+
+	original, err := ioutil.ReadFile(e.Backup)
+	if err != nil {
+		return err
+	}
+	if err := fileutil.WriteFileAtomic(e.File, original); err != nil {
+		return err
+	}
+
+	return os.Remove(e.Backup)
+}
+
+// LoadManifest reads manifest from the given path
+func LoadManifest(path string) (Manifest, error) {
+	manifest := Manifest{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("cannot parse backup manifest %s: %s", path, err)
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,58 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestRestore(t *testing.T) {
+	hash := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	testCases := []struct {
+		name            string
+		current         string
+		force           bool
+		expectedContent string
+		expectedErrors  int
+	}{
+		{name: "unchanged file", current: "new", expectedContent: "old"},
+		{name: "changed file", current: "edited", expectedContent: "edited", expectedErrors: 1},
+		{name: "changed file with force", current: "edited", force: true, expectedContent: "old"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			dir := t.TempDir()
+			file := filepath.Join(dir, "main.tf")
+			backup := file + ".bak"
+			assert.NoError(ioutil.WriteFile(file, []byte(tc.current), 0644))
+			assert.NoError(ioutil.WriteFile(backup, []byte("old"), 0644))
+
+			manifestPath := filepath.Join(dir, DefaultManifestFile)
+			assert.NoError(Manifest{Files: []Entry{{File: file, Backup: backup, SHA256: hash("new")}}}.Save(manifestPath))
+			manifest, err := LoadManifest(manifestPath)
+			assert.NoError(err)
+
+			restored, errs := manifest.Restore(tc.force)
+			assert.Equal(tc.expectedErrors, len(errs))
+			assert.Equal(1-tc.expectedErrors, len(restored))
+
+			content, err := ioutil.ReadFile(file)
+			assert.NoError(err)
+			assert.Equal(tc.expectedContent, string(content))
+
+			_, err = os.Stat(backup)
+			assert.Equal(tc.expectedErrors == 0, os.IsNotExist(err))
+		})
+	}
+}
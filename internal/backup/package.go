@@ -0,0 +1,20 @@
+package backup
+
+// DefaultManifestFile is the name of manifest file describing backups of the last run
+const DefaultManifestFile = ".tf-module-update-backup.json"
+
+// Manifest lists files written during a run with their backups
+type Manifest struct {
+	Files []Entry `json:"files"`
+}
+
+// Entry describes a single written file
+type Entry struct {
+	File string `json:"file"`
+
+	// Backup is the path of the file with original content
+	Backup string `json:"backup"`
+
+	// SHA256 is hex encoded hash of the content written during the run
+	SHA256 string `json:"sha256"`
+}
@@ -0,0 +1,80 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+// FileName is the name of changelog file looked up in the submodule folder and in the repository root
+const FileName = "CHANGELOG.md"
+
+var (
+	heading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+
+	// headingVersion matches versions in headings like "## [1.2.0] - 2021-01-01", "## v1.2.0" or "# Version 1.2.0"
+	headingVersion = regexp.MustCompile(`(?i)^\[?(?:version\s+)?(v?\d+(?:\.\d+){0,2}(?:-[0-9a-z.-]+)?)\]?(?:\s|\(|$)`)
+)
+
+// Sections returns sections of Markdown changelog for versions after from up to and including to, in the file order
+//
+// Sections are detected by headings which start with a version, the first such heading sets the level of
+// all sections. Nothing is returned if from or to is not a version
+func Sections(content string, from, to module.Revision) []string {
+	fromVersion, ok := from.Version()
+	if !ok {
+		return nil
+	}
+	toVersion, ok := to.Version()
+	if !ok {
+		return nil
+	}
+
+	sections := []string{}
+	level := 0
+	var current []string
+	closeSection := func() {
+		if current != nil {
+			sections = append(sections, strings.TrimSpace(strings.Join(current, "\n")))
+			current = nil
+		}
+	}
+
+	fenced := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+		}
+
+		if m := heading.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil && !fenced {
+			version, isVersion := headingRevision(m[2])
+			if isVersion && level == 0 {
+				level = len(m[1])
+			}
+
+			if level > 0 && len(m[1]) <= level {
+				closeSection()
+				if isVersion && len(m[1]) == level && version.Compare(fromVersion) > 0 && version.Compare(toVersion) <= 0 {
+					current = []string{}
+				}
+			}
+		}
+
+		if current != nil {
+			current = append(current, strings.TrimRight(line, "\r"))
+		}
+	}
+	closeSection()
+
+	return sections
+}
+
+func headingRevision(text string) (module.Version, bool) {
+	m := headingVersion.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return module.Version{}, false
+	}
+
+	return module.Revision(m[1]).Version()
+}
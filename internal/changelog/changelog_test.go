@@ -0,0 +1,90 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+const keepAChangelog = `# Changelog
+
+All notable changes are documented here.
+
+## [Unreleased]
+
+- Work in progress
+
+## [1.5.0] - 2021-03-01
+
+### Added
+
+- IPv6 support
+
+` + "```hcl" + `
+# not a heading
+` + "```" + `
+
+## [1.4.0] - 2021-02-01
+
+- Fix NAT gateway tags
+
+## [1.2.0] - 2021-01-01
+
+- Initial release
+`
+
+func TestSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		from     module.Revision
+		to       module.Revision
+		expected []string
+	}{
+		{
+			name:    "keep a changelog",
+			content: keepAChangelog,
+			from:    "v1.2.0",
+			to:      "v1.5.0",
+			expected: []string{
+				"## [1.5.0] - 2021-03-01\n\n### Added\n\n- IPv6 support\n\n```hcl\n# not a heading\n```",
+				"## [1.4.0] - 2021-02-01\n\n- Fix NAT gateway tags",
+			},
+		},
+		{
+			name:     "upper bound is inclusive",
+			content:  keepAChangelog,
+			from:     "v1.2.0",
+			to:       "v1.4.0",
+			expected: []string{"## [1.4.0] - 2021-02-01\n\n- Fix NAT gateway tags"},
+		},
+		{
+			name:     "plain version headings",
+			content:  "# v2.0.0 (2021-05-01)\n\nBreaking\n\n# Version 1.1.0\n\nFeature\n",
+			from:     "1.0.0",
+			to:       "2.0.0",
+			expected: []string{"# v2.0.0 (2021-05-01)\n\nBreaking", "# Version 1.1.0\n\nFeature"},
+		},
+		{
+			name:     "no versions",
+			content:  "# Notes\n\nNothing here\n",
+			from:     "v1.0.0",
+			to:       "v2.0.0",
+			expected: []string{},
+		},
+		{
+			name:     "revisions which are not versions",
+			content:  keepAChangelog,
+			from:     "main",
+			to:       "v1.5.0",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testhelpers.Assert(t).Equal(tt.expected, Sections(tt.content, tt.from, tt.to))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package compat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/hcl/v2/json"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// interfaceSchema selects blocks declaring the module interface
+var interfaceSchema = &hcl.BodySchema{Blocks: []hcl.BlockHeaderSchema{
+	{Type: "variable", LabelNames: []string{"name"}},
+	{Type: "output", LabelNames: []string{"name"}},
+}}
+
+// variableSchema selects arguments of variable block which matter for callers
+var variableSchema = &hcl.BodySchema{Attributes: []hcl.AttributeSchema{{Name: "default"}}}
+
+// ParseInterface collects variables and outputs declared in files of a module folder, keyed by file name,
+// files with ".json" suffix are parsed as Terraform JSON syntax
+func ParseInterface(files map[string][]byte) (Interface, error) {
+	i := Interface{Variables: map[string]Variable{}, Outputs: map[string]bool{}}
+	for name, src := range files {
+		var file *hcl.File
+		var diags hcl.Diagnostics
+		if strings.HasSuffix(name, ".json") {
+			file, diags = json.Parse(src, name)
+		} else {
+			file, diags = hclsyntax.ParseConfig(src, name, hcl.InitialPos)
+		}
+		if diags.HasErrors() {
+			return i, fmt.Errorf("parsing HCL syntax failed: %s", diags.Error())
+		}
+
+		content, _, diags := file.Body.PartialContent(interfaceSchema)
+		if diags.HasErrors() {
+			return i, fmt.Errorf("%s: %s", name, diags.Error())
+		}
+		for _, block := range content.Blocks {
+			switch block.Type {
+			case "variable":
+				v := Variable{Required: true}
+				body, _, _ := block.Body.PartialContent(variableSchema)
+				if attr, ok := body.Attributes["default"]; ok {
+					v.Required = false
+					v.Default = normalizeExpression(attr.Expr.Range().SliceBytes(src))
+				}
+				i.Variables[block.Labels[0]] = v
+			case "output":
+				i.Outputs[block.Labels[0]] = true
+			}
+		}
+	}
+
+	return i, nil
+}
+
+// Check compares interfaces of the old and the new revision of the module and
+// reports differences which concern the call setting the given arguments
+//
+// Findings are ordered by kind, from the most severe, and by name
+func Check(old, new Interface, arguments []string) []updater.InterfaceFinding {
+	set := map[string]bool{}
+	for _, a := range arguments {
+		set[a] = true
+	}
+
+	findings := []updater.InterfaceFinding{}
+	for name, v := range new.Variables {
+		previous, existed := old.Variables[name]
+		switch {
+		case v.Required && (!existed || !previous.Required):
+			f := updater.InterfaceFinding{Kind: KindRequiredVariable, Name: name, Breaking: !set[name]}
+			if existed {
+				f.Message = fmt.Sprintf("variable %q became required", name)
+			} else {
+				f.Message = fmt.Sprintf("required variable %q is added", name)
+			}
+			findings = append(findings, withCallState(f, set[name]))
+		case existed && !v.Required && !previous.Required && v.Default != previous.Default:
+			f := updater.InterfaceFinding{
+				Kind:    KindChangedDefault,
+				Name:    name,
+				Message: fmt.Sprintf("default of variable %q is changed from %s to %s", name, previous.Default, v.Default),
+			}
+			findings = append(findings, withCallState(f, set[name]))
+		}
+	}
+
+	for name := range old.Variables {
+		if _, ok := new.Variables[name]; !ok {
+			f := updater.InterfaceFinding{
+				Kind:     KindRemovedVariable,
+				Name:     name,
+				Breaking: set[name],
+				Message:  fmt.Sprintf("variable %q is removed", name),
+			}
+			findings = append(findings, withCallState(f, set[name]))
+		}
+	}
+
+	for name := range old.Outputs {
+		if !new.Outputs[name] {
+			findings = append(findings, updater.InterfaceFinding{
+				Kind:    KindRemovedOutput,
+				Name:    name,
+				Message: fmt.Sprintf("output %q is removed", name),
+			})
+		}
+	}
+
+	order := map[string]int{KindRequiredVariable: 0, KindRemovedVariable: 1, KindRemovedOutput: 2, KindChangedDefault: 3}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Kind != findings[j].Kind {
+			return order[findings[i].Kind] < order[findings[j].Kind]
+		}
+		return findings[i].Name < findings[j].Name
+	})
+
+	return findings
+}
+
+// withCallState adds to the message whether the call sets the variable
+func withCallState(f updater.InterfaceFinding, set bool) updater.InterfaceFinding {
+	if set {
+		f.Message += ", the call sets it"
+	} else {
+		f.Message += ", the call does not set it"
+	}
+
+	return f
+}
+
+// normalizeExpression formats expression, so defaults differing only in whitespace are equal
+func normalizeExpression(expr []byte) string {
+	formatted := hclwrite.Format(append([]byte("x = "), expr...))
+	text := strings.TrimPrefix(strings.TrimSpace(string(formatted)), "x = ")
+
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,92 @@
+package compat
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func TestParseInterface(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	i, err := ParseInterface(map[string][]byte{
+		"variables.tf": []byte(`
+variable "name" {
+  type = string
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {
+    env   = "prod"
+  }
+}
+
+variable "optional" {
+  default = null
+}
+`),
+		"outputs.tf": []byte(`
+output "id" {
+  value = aws_vpc.this.id
+}
+`),
+		"extra.tf.json": []byte(`{
+  "variable": {"region": {"type": "string"}, "azs": {"default": []}},
+  "output": {"arn": {"value": "${aws_vpc.this.arn}"}}
+}`),
+	})
+	assert.NoError(err)
+	assert.Equal(Interface{
+		Variables: map[string]Variable{
+			"name":     {Required: true},
+			"tags":     {Default: `{ env = "prod" }`},
+			"optional": {Default: "null"},
+			"region":   {Required: true},
+			"azs":      {Default: "[]"},
+		},
+		Outputs: map[string]bool{"id": true, "arn": true},
+	}, i)
+
+	_, err = ParseInterface(map[string][]byte{"main.tf": []byte(`variable "x" {`)})
+	assert.Equal(true, err != nil)
+}
+
+func TestCheck(t *testing.T) {
+	old := Interface{
+		Variables: map[string]Variable{
+			"name":    {Required: true},
+			"cidr":    {Default: `"10.0.0.0/16"`},
+			"azs":     {Default: "[]"},
+			"tags":    {Default: "{}"},
+			"legacy":  {Default: "false"},
+			"ignored": {Default: "1"},
+		},
+		Outputs: map[string]bool{"id": true, "arn": true},
+	}
+	new := Interface{
+		Variables: map[string]Variable{
+			"name":    {Required: true},
+			"cidr":    {Default: `"10.1.0.0/16"`},
+			"azs":     {Required: true},
+			"tags":    {Default: "{}"},
+			"region":  {Required: true},
+			"ipv6":    {Required: true},
+			"ignored": {Default: "2"},
+		},
+		Outputs: map[string]bool{"id": true},
+	}
+
+	assert := testhelpers.Assert(t)
+	assert.Equal([]updater.InterfaceFinding{
+		{Kind: KindRequiredVariable, Name: "azs", Breaking: false, Message: `variable "azs" became required, the call sets it`},
+		{Kind: KindRequiredVariable, Name: "ipv6", Breaking: true, Message: `required variable "ipv6" is added, the call does not set it`},
+		{Kind: KindRequiredVariable, Name: "region", Breaking: false, Message: `required variable "region" is added, the call sets it`},
+		{Kind: KindRemovedVariable, Name: "legacy", Breaking: true, Message: `variable "legacy" is removed, the call sets it`},
+		{Kind: KindRemovedOutput, Name: "arn", Message: `output "arn" is removed`},
+		{Kind: KindChangedDefault, Name: "cidr", Message: `default of variable "cidr" is changed from "10.0.0.0/16" to "10.1.0.0/16", the call does not set it`},
+		{Kind: KindChangedDefault, Name: "ignored", Message: `default of variable "ignored" is changed from 1 to 2, the call sets it`},
+	}, Check(old, new, []string{"name", "azs", "region", "legacy", "ignored"}))
+
+	assert.Equal([]updater.InterfaceFinding{}, Check(old, old, nil))
+}
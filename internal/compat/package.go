@@ -0,0 +1,31 @@
+package compat
+
+// Kinds of interface differences
+const (
+	// KindRequiredVariable is a variable without default added or a default removed from existing variable
+	KindRequiredVariable = "required-variable"
+
+	// KindRemovedVariable is a variable removed from the module
+	KindRemovedVariable = "removed-variable"
+
+	// KindRemovedOutput is an output removed from the module
+	KindRemovedOutput = "removed-output"
+
+	// KindChangedDefault is a changed default value of variable which stays optional
+	KindChangedDefault = "changed-default"
+)
+
+// Interface holds input variables and outputs of a module
+type Interface struct {
+	Variables map[string]Variable
+	Outputs   map[string]bool
+}
+
+// Variable is an input variable of a module
+type Variable struct {
+	// Required reports that the variable has no default value
+	Required bool
+
+	// Default is the expression of default value as written, with whitespace normalized
+	Default string
+}
@@ -0,0 +1,69 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes data to a temporary file in the same folder and renames it over the original one,
+// so the file is never left partially written
+//
+// Mode and owner of the existing file are kept, new files are created with 0644 mode.
+// Symbolic links are followed, so the link stays in place and its target is replaced
+func WriteFileAtomic(name string, data []byte) error {
+	return WriteFileAtomicAs(name, data, name)
+}
+
+// WriteFileAtomicAs is the same as WriteFileAtomic, but mode and owner are taken from reference file,
+// e.g. to keep a copy of file with the same permissions
+func WriteFileAtomicAs(name string, data []byte, reference string) error {
+	target, err := filepath.EvalSymlinks(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		target = name
+	} else if err != nil {
+		return err
+	}
+
+	mode := fs.FileMode(0644)
+	info, err := os.Stat(reference)
+	if err == nil {
+		mode = info.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// the temporary file is hidden, so it is never picked up by concurrent traversal
+	tmp, err := ioutil.TempFile(filepath.Dir(target), "."+filepath.Base(target)+".tmp-")
+	if err != nil {
+		return err
+	}
+	// after successful rename there is nothing to remove
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	if info != nil {
+		if err := preserveOwner(tmp.Name(), info); err != nil {
+			return fmt.Errorf("cannot keep owner of %s: %s", name, err)
+		}
+	}
+
+	return os.Rename(tmp.Name(), target)
+}
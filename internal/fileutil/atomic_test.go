@@ -0,0 +1,112 @@
+//go:build !windows
+// +build !windows
+
+package fileutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	testCases := []struct {
+		name         string
+		setup        func(dir string) string
+		expectedMode os.FileMode
+	}{
+		{
+			name: "mode is kept",
+			setup: func(dir string) string {
+				name := filepath.Join(dir, "main.tf")
+				if err := ioutil.WriteFile(name, []byte("old"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chmod(name, 0640); err != nil {
+					t.Fatal(err)
+				}
+				return name
+			},
+			expectedMode: 0640,
+		},
+		{
+			name: "new file",
+			setup: func(dir string) string {
+				return filepath.Join(dir, "main.tf")
+			},
+			expectedMode: 0644,
+		},
+		{
+			name: "symlink target is replaced",
+			setup: func(dir string) string {
+				target := filepath.Join(dir, "main.tf")
+				if err := ioutil.WriteFile(target, []byte("old"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				link := filepath.Join(dir, "link.tf")
+				if err := os.Symlink(target, link); err != nil {
+					t.Fatal(err)
+				}
+				return link
+			},
+			expectedMode: 0600,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			dir := t.TempDir()
+			name := tc.setup(dir)
+
+			assert.NoError(WriteFileAtomic(name, []byte("new")))
+
+			content, err := ioutil.ReadFile(name)
+			assert.NoError(err)
+			assert.Equal("new", string(content))
+
+			info, err := os.Stat(name)
+			assert.NoError(err)
+			assert.Equal(tc.expectedMode, info.Mode().Perm())
+
+			// temporary files must not be left behind
+			entries, err := os.ReadDir(dir)
+			assert.NoError(err)
+			for _, e := range entries {
+				assert.Equal(".tf", filepath.Ext(e.Name()))
+			}
+		})
+	}
+}
+
+func TestWriteFileAtomicAs(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	dir := t.TempDir()
+	reference := filepath.Join(dir, "main.tf")
+	assert.NoError(ioutil.WriteFile(reference, []byte("secret"), 0600))
+
+	name := reference + ".bak"
+	assert.NoError(WriteFileAtomicAs(name, []byte("secret"), reference))
+
+	info, err := os.Stat(name)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestWriteFileAtomicKeepsLink(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	dir := t.TempDir()
+	target := filepath.Join(dir, "main.tf")
+	link := filepath.Join(dir, "link.tf")
+	assert.NoError(ioutil.WriteFile(target, []byte("old"), 0644))
+	assert.NoError(os.Symlink(target, link))
+
+	assert.NoError(WriteFileAtomic(link, []byte("new")))
+
+	info, err := os.Lstat(link)
+	assert.NoError(err)
+	assert.Equal(os.ModeSymlink, info.Mode()&os.ModeSymlink)
+}
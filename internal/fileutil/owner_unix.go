@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package fileutil
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// preserveOwner changes owner of the file to the owner of original file, if they differ
+func preserveOwner(name string, original fs.FileInfo) error {
+	originalStat, ok := original.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || (stat.Uid == originalStat.Uid && stat.Gid == originalStat.Gid) {
+		return nil
+	}
+
+	return os.Chown(name, int(originalStat.Uid), int(originalStat.Gid))
+}
@@ -0,0 +1,11 @@
+//go:build windows
+// +build windows
+
+package fileutil
+
+import "io/fs"
+
+// preserveOwner does nothing on Windows, renamed files inherit permissions of the folder
+func preserveOwner(name string, original fs.FileInfo) error {
+	return nil
+}
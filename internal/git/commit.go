@@ -0,0 +1,157 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/inventory"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// ParseGrouping converts string to Grouping
+func ParseGrouping(s string) (Grouping, error) {
+	switch g := Grouping(s); g {
+	case GroupByRule, GroupByRepository:
+		return g, nil
+	}
+
+	return "", fmt.Errorf("unknown grouping %q, expected %s or %s", s, GroupByRule, GroupByRepository)
+}
+
+// GroupChanges splits changes into commits
+//
+// A file is committed as a whole, so groups which changed the same file are merged into one commit
+func GroupChanges(changes []updater.Change, grouping Grouping) ([]CommitGroup, error) {
+	if _, err := ParseGrouping(string(grouping)); err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	byKey := map[string][]updater.Change{}
+	for _, c := range changes {
+		key := ""
+		if grouping == GroupByRepository {
+			key = inventory.GroupKey(c.After)
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], c)
+	}
+	sort.Strings(keys)
+
+	// parent implements union-find over indexes of keys sharing files
+	parent := make([]int, len(keys))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	fileOwner := map[string]int{}
+	for i, key := range keys {
+		for _, c := range byKey[key] {
+			owner, ok := fileOwner[c.Call.File]
+			if !ok {
+				fileOwner[c.Call.File] = i
+				continue
+			}
+			a, b := find(owner), find(i)
+			if a > b {
+				a, b = b, a
+			}
+			parent[b] = a
+		}
+	}
+
+	groups := []CommitGroup{}
+	groupIndex := map[int]int{}
+	for i, key := range keys {
+		root := find(i)
+		index, ok := groupIndex[root]
+		if !ok {
+			index = len(groups)
+			groupIndex[root] = index
+			groups = append(groups, CommitGroup{})
+		}
+
+		g := &groups[index]
+		if key != "" {
+			g.Keys = append(g.Keys, key)
+		}
+		for _, c := range byKey[key] {
+			g.Changes = append(g.Changes, c)
+			if !contains(g.Files, c.Call.File) {
+				g.Files = append(g.Files, c.Call.File)
+			}
+		}
+		sort.Strings(g.Files)
+	}
+
+	return groups, nil
+}
+
+// Message builds commit message listing module sources with old and new revisions and touched files,
+// file paths are relative to dir
+func (g CommitGroup) Message(dir string) string {
+	subject := "Update module sources"
+	if len(g.Keys) > 0 {
+		subject = "Update " + strings.Join(g.Keys, ", ")
+	}
+
+	revisions := map[module.Revision]bool{}
+	lines := []string{}
+	for _, c := range g.Changes {
+		revisions[c.After.Revision] = true
+
+		line := inventory.GroupKey(c.Before) + ": " + c.Before.RevisionOrString() + " → " + c.After.RevisionOrString()
+		if inventory.GroupKey(c.Before) != inventory.GroupKey(c.After) {
+			line = c.Before.String() + " → " + c.After.String()
+		}
+		if !contains(lines, line) {
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+
+	if len(revisions) == 1 {
+		for r := range revisions {
+			if r != "" {
+				subject += " to " + string(r)
+			}
+		}
+	}
+
+	message := &strings.Builder{}
+	message.WriteString(subject + "\n\nModule sources:\n")
+	for _, l := range lines {
+		message.WriteString("- " + l + "\n")
+	}
+	message.WriteString("\nFiles:\n")
+	for _, f := range g.Files {
+		if rel, err := filepath.Rel(dir, f); err == nil {
+			f = filepath.ToSlash(rel)
+		}
+		message.WriteString("- " + f + "\n")
+	}
+
+	return message.String()
+}
+
+func contains(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func testChange(file, submodule string, before, after module.Revision) updater.Change {
+	source := module.Source{Scheme: "https", Host: "github.com", Module: "/example-org/modules.git", Submodule: submodule}
+	c := updater.Change{Call: updater.ModuleCall{File: file, Block: "module"}, Before: source, After: source}
+	c.Before.Revision = before
+	c.After.Revision = after
+
+	return c
+}
+
+func TestGroupChanges(t *testing.T) {
+	vpcProd := testChange("/repo/prod/main.tf", "//vpc", "v1", "v2")
+	vpcStage := testChange("/repo/stage/main.tf", "//vpc", "v1", "v2")
+	dbProd := testChange("/repo/prod/main.tf", "//db", "v1", "v2")
+	dbOther := testChange("/repo/other/main.tf", "//db", "v1", "v2")
+	sqs := testChange("/repo/queue/main.tf", "//sqs", "v3", "v4")
+
+	testCases := []struct {
+		name           string
+		changes        []updater.Change
+		grouping       Grouping
+		expectedResult []CommitGroup
+		expectedError  bool
+	}{
+		{
+			name:     "rule",
+			changes:  []updater.Change{vpcStage, vpcProd},
+			grouping: GroupByRule,
+			expectedResult: []CommitGroup{
+				{Changes: []updater.Change{vpcStage, vpcProd}, Files: []string{"/repo/prod/main.tf", "/repo/stage/main.tf"}},
+			},
+		},
+		{
+			name:     "repository groups sharing a file are merged",
+			changes:  []updater.Change{vpcProd, vpcStage, sqs, dbProd, dbOther},
+			grouping: GroupByRepository,
+			expectedResult: []CommitGroup{
+				{
+					Keys:    []string{"github.com/example-org/modules//db", "github.com/example-org/modules//vpc"},
+					Changes: []updater.Change{dbProd, dbOther, vpcProd, vpcStage},
+					Files:   []string{"/repo/other/main.tf", "/repo/prod/main.tf", "/repo/stage/main.tf"},
+				},
+				{
+					Keys:    []string{"github.com/example-org/modules//sqs"},
+					Changes: []updater.Change{sqs},
+					Files:   []string{"/repo/queue/main.tf"},
+				},
+			},
+		},
+		{
+			name:          "unknown grouping",
+			grouping:      Grouping("file"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			groups, err := GroupChanges(tc.changes, tc.grouping)
+			if tc.expectedError {
+				assert.Equal(true, err != nil)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expectedResult, groups)
+		})
+	}
+}
+
+func TestCommitGroupMessage(t *testing.T) {
+	testCases := []struct {
+		name           string
+		group          CommitGroup
+		expectedResult string
+	}{
+		{
+			name: "single revision",
+			group: CommitGroup{
+				Keys:    []string{"github.com/example-org/modules//vpc"},
+				Changes: []updater.Change{testChange("/repo/prod/main.tf", "//vpc", "v1", "v2"), testChange("/repo/stage/main.tf", "//vpc", "v0", "v2")},
+				Files:   []string{"/repo/prod/main.tf", "/repo/stage/main.tf"},
+			},
+			expectedResult: `Update github.com/example-org/modules//vpc to v2
+
+Module sources:
+- github.com/example-org/modules//vpc: v0 → v2
+- github.com/example-org/modules//vpc: v1 → v2
+
+Files:
+- prod/main.tf
+- stage/main.tf
+`,
+		},
+		{
+			name: "rule with different revisions",
+			group: CommitGroup{
+				Changes: []updater.Change{testChange("/repo/main.tf", "//vpc", "v1", "v2"), testChange("/repo/main.tf", "//db", "v1", "v3")},
+				Files:   []string{"/repo/main.tf"},
+			},
+			expectedResult: `Update module sources
+
+Module sources:
+- github.com/example-org/modules//db: v1 → v3
+- github.com/example-org/modules//vpc: v1 → v2
+
+Files:
+- main.tf
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			assert.Equal(tc.expectedResult, tc.group.Message("/repo"))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package git
+
+import (
+	"time"
+
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// Repository is a local git working tree or a bare repository, commands are run by git binary found in PATH
+type Repository struct {
+	// dir is the top-level folder of the working tree or the folder of a bare repository
+	dir string
+}
+
+// Tag is a tag of repository with its date
+type Tag struct {
+	Name string
+	Date time.Time
+}
+
+// Grouping defines how changes are split into commits
+type Grouping string
+
+const (
+	// GroupByRule puts all changes made by the update rule into a single commit
+	GroupByRule Grouping = "rule"
+
+	// GroupByRepository makes a commit per module repository and submodule
+	GroupByRepository Grouping = "repository"
+)
+
+// CommitGroup holds changes committed together
+type CommitGroup struct {
+	// Keys are the module repositories of changes, empty for GroupByRule
+	Keys    []string
+	Changes []updater.Change
+	Files   []string
+}
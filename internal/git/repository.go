@@ -0,0 +1,205 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dir returns top-level folder of the working tree
+func (r *Repository) Dir() string {
+	return r.dir
+}
+
+// IsDirty reports if tracked files have uncommitted changes, untracked files are not taken into account
+func (r *Repository) IsDirty() (bool, error) {
+	out, err := r.run(nil, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return false, err
+	}
+
+	return out != "", nil
+}
+
+// CreateBranch creates a new branch from the current HEAD and switches to it
+func (r *Repository) CreateBranch(name string) error {
+	_, err := r.run(nil, "checkout", "-b", name)
+	return err
+}
+
+// Commit stages the files and commits only them, other staged changes are left as they are,
+// abbreviated hash of the commit is returned
+func (r *Repository) Commit(message string, files []string) (string, error) {
+	if _, err := r.run(nil, append([]string{"add", "--"}, files...)...); err != nil {
+		return "", err
+	}
+
+	args := append([]string{"commit", "--file", "-", "--"}, files...)
+	if _, err := r.run(strings.NewReader(message), args...); err != nil {
+		return "", err
+	}
+
+	return r.run(nil, "rev-parse", "--short", "HEAD")
+}
+
+// ResolveCommit returns full hash of the commit the ref, e.g. a tag or a branch, points to
+func (r *Repository) ResolveCommit(ref string) (string, error) {
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid ref %q", ref)
+	}
+
+	commit, err := r.run(nil, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %q in %s", ref, r.dir)
+	}
+
+	return commit, nil
+}
+
+// IsDir reports if the path is a folder in the tree of the commit, path is slash separated and relative to the repository root
+func (r *Repository) IsDir(commit, path string) bool {
+	kind, err := r.run(nil, "cat-file", "-t", commit+":"+path)
+	return err == nil && kind == "tree"
+}
+
+// Log returns subjects of commits reachable from to but not from from, the newest first,
+// only commits touching the path are returned unless it is empty
+func (r *Repository) Log(from, to, path string) ([]string, error) {
+	args := []string{"log", "--format=%s", from + ".." + to}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+
+	out, err := r.run(nil, args...)
+	if err != nil || out == "" {
+		return []string{}, err
+	}
+
+	return strings.Split(out, "\n"), nil
+}
+
+// ReadFile returns content of the file at the commit with surrounding whitespace trimmed,
+// path is slash separated and relative to the repository root
+func (r *Repository) ReadFile(commit, path string) (string, error) {
+	return r.run(nil, "cat-file", "blob", commit+":"+path)
+}
+
+// ListFiles returns slash separated paths of files and folders directly in the folder at the commit,
+// the folder is relative to the repository root, empty for the root itself
+func (r *Repository) ListFiles(commit, dir string) ([]string, error) {
+	args := []string{"ls-tree", "--name-only", commit}
+	if dir != "" {
+		args = append(args, "--", dir+"/")
+	}
+
+	out, err := r.run(nil, args...)
+	if err != nil || out == "" {
+		return []string{}, err
+	}
+
+	return strings.Split(out, "\n"), nil
+}
+
+// Tags returns all tags with their dates, the date of annotated tag is when it was created,
+// the date of lightweight tag is the date of its commit
+func (r *Repository) Tags() ([]Tag, error) {
+	out, err := r.run(nil, "for-each-ref", "--format=%(refname)%09%(creatordate:unix)", "refs/tags")
+	if err != nil || out == "" {
+		return []Tag{}, err
+	}
+
+	tags := []Tag{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("unexpected tag line: %s", line)
+		}
+		seconds, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected tag date: %s", line)
+		}
+		tags = append(tags, Tag{Name: strings.TrimPrefix(fields[0], "refs/tags/"), Date: time.Unix(seconds, 0)})
+	}
+
+	return tags, nil
+}
+
+func (r *Repository) run(stdin io.Reader, args ...string) (string, error) {
+	return runGit(r.dir, stdin, args...)
+}
+
+func runGit(dir string, stdin io.Reader, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdin = stdin
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], message)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Open finds the working tree which the path belongs to, path might be a file or a folder
+func Open(path string) (*Repository, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path
+	if !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	top, err := runGit(dir, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git working tree: %s", path, err)
+	}
+
+	return &Repository{dir: filepath.FromSlash(top)}, nil
+}
+
+// OpenDir opens the repository located exactly in the folder, it might be a bare one like a mirror clone
+func OpenDir(dir string) (*Repository, error) {
+	if _, err := runGit(dir, nil, "rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("%s is not a git repository: %s", dir, err)
+	}
+
+	return &Repository{dir: dir}, nil
+}
+
+// OpenAll finds the working tree of all paths, they must belong to the same one
+func OpenAll(paths []string) (*Repository, error) {
+	var repository *Repository
+	for _, p := range paths {
+		r, err := Open(p)
+		if err != nil {
+			return nil, err
+		}
+		if repository != nil && repository.dir != r.dir {
+			return nil, errors.New("paths belong to different git working trees: " + repository.dir + ", " + r.dir)
+		}
+		repository = r
+	}
+
+	if repository == nil {
+		return nil, errors.New("no paths given")
+	}
+
+	return repository, nil
+}
@@ -0,0 +1,169 @@
+package git
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func writeTestFile(t *testing.T, name, content string) {
+	if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepositoryCommit(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	dir := testhelpers.NewGitRepository(t, "main.tf", "v1")
+
+	repository, err := Open(filepath.Join(dir, "main.tf"))
+	assert.NoError(err)
+
+	dirty, err := repository.IsDirty()
+	assert.NoError(err)
+	assert.Equal(false, dirty)
+
+	// untracked files do not make the tree dirty
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "")
+	dirty, err = repository.IsDirty()
+	assert.NoError(err)
+	assert.Equal(false, dirty)
+
+	writeTestFile(t, filepath.Join(dir, "main.tf"), "v2")
+	dirty, err = repository.IsDirty()
+	assert.NoError(err)
+	assert.Equal(true, dirty)
+
+	assert.NoError(repository.CreateBranch("bump-vpc"))
+	_, err = repository.Commit("Update vpc to v2\n\nbody\n", []string{filepath.Join(dir, "main.tf")})
+	assert.NoError(err)
+
+	branch, err := repository.run(nil, "rev-parse", "--abbrev-ref", "HEAD")
+	assert.NoError(err)
+	assert.Equal("bump-vpc", branch)
+
+	subject, err := repository.run(nil, "log", "-1", "--format=%s")
+	assert.NoError(err)
+	assert.Equal("Update vpc to v2", subject)
+
+	dirty, err = repository.IsDirty()
+	assert.NoError(err)
+	assert.Equal(false, dirty)
+}
+
+func TestOpenAll(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	first := testhelpers.NewGitRepository(t, "main.tf", "v1")
+	second := testhelpers.NewGitRepository(t, "main.tf", "v1")
+
+	_, err := OpenAll([]string{first, filepath.Join(first, "main.tf")})
+	assert.NoError(err)
+
+	_, err = OpenAll([]string{first, second})
+	assert.Equal(true, err != nil)
+
+	_, err = Open(t.TempDir())
+	assert.Equal(true, err != nil)
+}
+
+func TestResolveCommit(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	dir := testhelpers.NewGitRepository(t, "main.tf", "v1")
+
+	_, err := runGit(dir, nil, "tag", "-a", "-m", "release", "v1.0.0")
+	assert.NoError(err)
+	head, err := runGit(dir, nil, "rev-parse", "HEAD")
+	assert.NoError(err)
+
+	mirror := filepath.Join(t.TempDir(), "mirror.git")
+	_, err = runGit(dir, nil, "clone", "-q", "--mirror", dir, mirror)
+	assert.NoError(err)
+
+	repository, err := OpenDir(mirror)
+	assert.NoError(err)
+
+	// annotated tags are peeled to the commit
+	commit, err := repository.ResolveCommit("v1.0.0")
+	assert.NoError(err)
+	assert.Equal(head, commit)
+
+	_, err = repository.ResolveCommit("v2.0.0")
+	assert.Equal(true, err != nil)
+
+	_, err = repository.ResolveCommit("--all")
+	assert.Equal(true, err != nil)
+
+	_, err = OpenDir(t.TempDir())
+	assert.Equal(true, err != nil)
+}
+
+func TestLog(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	dir := testhelpers.NewGitRepository(t, "main.tf", "v1")
+	repository, err := OpenDir(dir)
+	assert.NoError(err)
+
+	from, err := repository.ResolveCommit("HEAD")
+	assert.NoError(err)
+
+	testhelpers.CommitFiles(t, dir, "Add vpc", "vpc/main.tf", "Add vpc")
+	testhelpers.CommitFiles(t, dir, "Update root", "main.tf", "Update root")
+	testhelpers.CommitFiles(t, dir, "Add vpc variables", "vpc/variables.tf", "Add vpc variables")
+
+	subjects, err := repository.Log(from, "HEAD", "vpc")
+	assert.NoError(err)
+	assert.Equal([]string{"Add vpc variables", "Add vpc"}, subjects)
+
+	subjects, err = repository.Log(from, "HEAD", "")
+	assert.NoError(err)
+	assert.Equal([]string{"Add vpc variables", "Update root", "Add vpc"}, subjects)
+
+	subjects, err = repository.Log("HEAD", "HEAD", "")
+	assert.NoError(err)
+	assert.Equal([]string{}, subjects)
+
+	files, err := repository.ListFiles("HEAD", "vpc")
+	assert.NoError(err)
+	assert.Equal([]string{"vpc/main.tf", "vpc/variables.tf"}, files)
+
+	files, err = repository.ListFiles("HEAD", "")
+	assert.NoError(err)
+	assert.Equal([]string{"main.tf", "vpc"}, files)
+
+	content, err := repository.ReadFile("HEAD", "vpc/main.tf")
+	assert.NoError(err)
+	assert.Equal("Add vpc", content)
+
+	assert.Equal(true, repository.IsDir("HEAD", "vpc"))
+	assert.Equal(false, repository.IsDir("HEAD", "vpc/main.tf"))
+	assert.Equal(false, repository.IsDir(from, "vpc"))
+}
+
+func TestTags(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	dir := testhelpers.NewGitRepository(t, "main.tf", "v1")
+	repository, err := OpenDir(dir)
+	assert.NoError(err)
+
+	tags, err := repository.Tags()
+	assert.NoError(err)
+	assert.Equal([]Tag{}, tags)
+
+	_, err = runGit(dir, nil, "tag", "v1.0.0")
+	assert.NoError(err)
+	_, err = runGit(dir, nil, "tag", "-a", "-m", "release", "v1.1.0")
+	assert.NoError(err)
+	// a branch with the same name does not confuse tag names
+	_, err = runGit(dir, nil, "branch", "v1.1.0")
+	assert.NoError(err)
+
+	tags, err = repository.Tags()
+	assert.NoError(err)
+	assert.Equal(2, len(tags))
+	assert.Equal("v1.0.0", tags[0].Name)
+	assert.Equal("v1.1.0", tags[1].Name)
+	assert.Equal(true, time.Since(tags[1].Date) < time.Hour)
+}
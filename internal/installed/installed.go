@@ -0,0 +1,133 @@
+package installed
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// registryHost is the host Terraform adds to registry sources without explicit one
+const registryHost = "registry.terraform.io"
+
+// gitPolicy makes git sources comparable with ones normalized by Terraform,
+// e.g. "github.com/org/repo" is installed as "git::https://github.com/org/repo.git"
+var gitPolicy = module.NormalizePolicy{GitPrefix: true, GitSuffix: true}
+
+// LoadManifest reads manifest of root module folder, error satisfies os.IsNotExist if modules were never installed
+func LoadManifest(dir, dataDir string) (Manifest, error) {
+	manifest := Manifest{}
+	path := filepath.Join(dir, dataDir, filepath.FromSlash(ManifestFile))
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return manifest, nil
+}
+
+// Compare finds module calls of root module folder which differ from the installed ones
+//
+// Only "module" blocks of the folder itself are compared, nested modules are installed
+// from their parent, so their records are not taken into account
+func Compare(dir string, calls []updater.ModuleCall, manifest Manifest) []Finding {
+	installed := map[string]Record{}
+	for _, r := range manifest.Modules {
+		if r.Key != "" && !strings.Contains(r.Key, ".") {
+			installed[r.Key] = r
+		}
+	}
+
+	findings := []Finding{}
+	declared := map[string]bool{}
+	for _, c := range calls {
+		if c.Block != "module" || filepath.Dir(c.File) != dir {
+			continue
+		}
+		declared[c.Name] = true
+
+		finding := Finding{Dir: dir, Key: c.Name, File: c.File, Declared: withVersion(c.Source, c.Version)}
+		r, ok := installed[c.Name]
+		if !ok {
+			finding.Kind = FindingNotInstalled
+			findings = append(findings, finding)
+			continue
+		}
+
+		if !matches(c, r) {
+			finding.Kind = FindingStale
+			finding.Installed = withVersion(r.Source, r.Version)
+			findings = append(findings, finding)
+		}
+	}
+
+	for key, r := range installed {
+		if !declared[key] {
+			findings = append(findings, Finding{Kind: FindingOrphaned, Dir: dir, Key: key, Installed: withVersion(r.Source, r.Version)})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Key < findings[j].Key
+	})
+
+	return findings
+}
+
+// matches reports if the installed record satisfies the declared call
+func matches(c updater.ModuleCall, r Record) bool {
+	declared, err := module.ParseSource(c.Source)
+	if err != nil {
+		return c.Source == r.Source
+	}
+
+	switch declared.Kind() {
+	case module.KindGit:
+		installed, err := module.ParseSource(r.Source)
+		if err != nil {
+			return false
+		}
+		return module.Normalize(declared, gitPolicy).String() == module.Normalize(installed, gitPolicy).String()
+	case module.KindRegistry:
+		if registryAddress(c.Source) != registryAddress(r.Source) {
+			return false
+		}
+		if c.Version == "" {
+			return true
+		}
+		constraint, err := module.ParseConstraint(c.Version)
+		if err != nil {
+			return c.Version == r.Version
+		}
+		return constraint.Allows(module.Revision(r.Version))
+	}
+
+	return c.Source == r.Source
+}
+
+// registryAddress returns registry source with explicit host, e.g. "registry.terraform.io/org/name/provider"
+func registryAddress(source string) string {
+	address := strings.ToLower(strings.TrimPrefix(source, module.RegistryScheme+":///"))
+	if strings.Count(address, "/") == 2 {
+		return registryHost + "/" + address
+	}
+
+	return address
+}
+
+func withVersion(source, version string) string {
+	if version == "" {
+		return source
+	}
+
+	return source + " (" + version + ")"
+}
@@ -0,0 +1,69 @@
+package installed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func TestCompare(t *testing.T) {
+	dir := filepath.FromSlash("/infra/prod")
+	file := filepath.Join(dir, "main.tf")
+	manifest := Manifest{Modules: []Record{
+		{Key: "", Source: "", Dir: "."},
+		{Key: "vpc", Source: "git::https://github.com/org/modules.git//vpc?ref=v1.0.0", Dir: ".terraform/modules/vpc"},
+		{Key: "vpc.subnets", Source: "./modules/subnets", Dir: ".terraform/modules/vpc/modules/subnets"},
+		{Key: "dns", Source: "git::https://github.com/org/modules.git//dns?ref=v1.0.0", Dir: ".terraform/modules/dns"},
+		{Key: "eks", Source: "registry.terraform.io/terraform-aws-modules/eks/aws", Version: "18.2.0", Dir: ".terraform/modules/eks"},
+		{Key: "rds", Source: "registry.terraform.io/terraform-aws-modules/rds/aws", Version: "4.0.0", Dir: ".terraform/modules/rds"},
+		{Key: "local", Source: "./modules/local", Dir: "modules/local"},
+		{Key: "old", Source: "git::https://github.com/org/modules.git//old?ref=v1.0.0", Dir: ".terraform/modules/old"},
+	}}
+	calls := []updater.ModuleCall{
+		// shorthand is the same source as the normalized one
+		{File: file, Name: "vpc", Block: "module", Source: "github.com/org/modules//vpc?ref=v1.0.0"},
+		{File: file, Name: "dns", Block: "module", Source: "git::https://github.com/org/modules.git//dns?ref=v1.1.0"},
+		{File: file, Name: "eks", Block: "module", Source: "terraform-aws-modules/eks/aws", Version: "~> 18.0"},
+		{File: file, Name: "rds", Block: "module", Source: "terraform-aws-modules/rds/aws", Version: "~> 5.0"},
+		{File: file, Name: "local", Block: "module", Source: "./modules/local"},
+		{File: file, Name: "new", Block: "module", Source: "./modules/new"},
+		// calls of other folders and test files are not compared
+		{File: filepath.Join(dir, "modules", "local", "main.tf"), Name: "old", Block: "module", Source: "./x"},
+		{File: filepath.Join(dir, "tests", "main.tftest.hcl"), Name: "old", Block: "run.module", Source: "./x"},
+	}
+
+	assert := testhelpers.Assert(t)
+	assert.Equal([]Finding{
+		{Kind: FindingStale, Dir: dir, Key: "dns", File: file,
+			Declared:  "git::https://github.com/org/modules.git//dns?ref=v1.1.0",
+			Installed: "git::https://github.com/org/modules.git//dns?ref=v1.0.0"},
+		{Kind: FindingNotInstalled, Dir: dir, Key: "new", File: file, Declared: "./modules/new"},
+		{Kind: FindingOrphaned, Dir: dir, Key: "old", Installed: "git::https://github.com/org/modules.git//old?ref=v1.0.0"},
+		{Kind: FindingStale, Dir: dir, Key: "rds", File: file,
+			Declared:  "terraform-aws-modules/rds/aws (~> 5.0)",
+			Installed: "registry.terraform.io/terraform-aws-modules/rds/aws (4.0.0)"},
+	}, Compare(dir, calls, manifest))
+}
+
+func TestLoadManifest(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	dir := t.TempDir()
+
+	_, err := LoadManifest(dir, DefaultDataDir)
+	assert.Equal(true, os.IsNotExist(err))
+
+	path := filepath.Join(dir, DefaultDataDir, "modules")
+	assert.NoError(os.MkdirAll(path, 0755))
+	content := `{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"vpc","Source":"git::https://github.com/org/modules.git?ref=v1","Dir":".terraform/modules/vpc"}]}`
+	assert.NoError(os.WriteFile(filepath.Join(path, "modules.json"), []byte(content), 0644))
+
+	manifest, err := LoadManifest(dir, DefaultDataDir)
+	assert.NoError(err)
+	assert.Equal(Manifest{Modules: []Record{
+		{Key: "", Source: "", Dir: "."},
+		{Key: "vpc", Source: "git::https://github.com/org/modules.git?ref=v1", Dir: ".terraform/modules/vpc"},
+	}}, manifest)
+}
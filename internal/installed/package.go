@@ -0,0 +1,57 @@
+package installed
+
+// ManifestFile is the path of the manifest of installed modules inside Terraform data folder
+const ManifestFile = "modules/modules.json"
+
+// DefaultDataDir is the folder where "terraform init" installs modules to
+const DefaultDataDir = ".terraform"
+
+// Manifest is the list of modules installed by "terraform init" for a root module
+type Manifest struct {
+	Modules []Record `json:"Modules"`
+}
+
+// Record is a single installed module
+type Record struct {
+	// Key is the module call name, nested calls are joined with dots, e.g. "vpc.subnets", empty for the root module
+	Key string `json:"Key"`
+
+	// Source is the module source, normalized by Terraform
+	Source string `json:"Source"`
+
+	// Version is the installed version of registry module
+	Version string `json:"Version,omitempty"`
+
+	Dir string `json:"Dir"`
+}
+
+// FindingKind describes how declared module call differs from the installed one
+type FindingKind string
+
+const (
+	// FindingStale means the source or version was changed after "terraform init"
+	FindingStale FindingKind = "stale"
+
+	// FindingNotInstalled means the module call was added after "terraform init"
+	FindingNotInstalled FindingKind = "not-installed"
+
+	// FindingOrphaned means the installed module is not called anymore
+	FindingOrphaned FindingKind = "orphaned"
+)
+
+// Finding is a difference between declared and installed module
+type Finding struct {
+	Kind FindingKind `json:"kind"`
+
+	// Dir is the root module folder
+	Dir string `json:"dir"`
+
+	// Key is the module call name
+	Key string `json:"key"`
+
+	// File is the file with declared module call, empty for orphaned modules
+	File string `json:"file"`
+
+	Declared  string `json:"declared"`
+	Installed string `json:"installed"`
+}
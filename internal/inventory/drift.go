@@ -0,0 +1,117 @@
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// NewUsages parses sources of module calls
+//
+// Calls with sources that cannot be parsed are skipped. Registry modules keep their version
+// in a separate attribute, so it is used as revision when source has no revision
+func NewUsages(calls []updater.ModuleCall) []Usage {
+	usages := []Usage{}
+	for _, c := range calls {
+		source, err := module.ParseSource(c.Source)
+		if err != nil {
+			continue
+		}
+
+		revision := source.Revision
+		if revision == "" {
+			revision = module.Revision(c.Version)
+		}
+
+		usages = append(usages, Usage{Call: c, Source: source, Revision: revision})
+	}
+
+	return usages
+}
+
+// GroupKey returns canonical repository with submodule, so different spellings of the same module are grouped together
+func GroupKey(source module.Source) string {
+	submodule := strings.Trim(source.Submodule, "/")
+	if submodule == "" {
+		return source.Repository()
+	}
+
+	return source.Repository() + "//" + submodule
+}
+
+// Drift finds modules which are used with more than one revision
+//
+// Different spellings of the same version, e.g. "v1.0.0" and "1.0.0", are counted as one revision
+// shown with the first spelling in sort order. Local modules and calls without revision are not taken into account
+func Drift(usages []Usage) []DriftGroup {
+	keys := []string{}
+	revisions := map[string]map[string]*RevisionUsage{}
+	for _, u := range usages {
+		if u.Source.Kind() == module.KindLocal || u.Revision == "" {
+			continue
+		}
+
+		key := GroupKey(u.Source)
+		if _, ok := revisions[key]; !ok {
+			keys = append(keys, key)
+			revisions[key] = map[string]*RevisionUsage{}
+		}
+		usage, ok := revisions[key][revisionKey(u.Revision)]
+		if !ok {
+			usage = &RevisionUsage{Revision: u.Revision}
+			revisions[key][revisionKey(u.Revision)] = usage
+		}
+		if u.Revision < usage.Revision {
+			usage.Revision = u.Revision
+		}
+		usage.Files = append(usage.Files, u.Call.File)
+	}
+	sort.Strings(keys)
+
+	groups := []DriftGroup{}
+	for _, key := range keys {
+		if len(revisions[key]) < 2 {
+			continue
+		}
+
+		usages := make([]RevisionUsage, 0, len(revisions[key]))
+		for _, r := range revisions[key] {
+			usages = append(usages, *r)
+		}
+		sort.Slice(usages, func(i, j int) bool {
+			return module.CompareRevisions(usages[i].Revision, usages[j].Revision) > 0
+		})
+
+		group := DriftGroup{Key: key, Newest: usages[0].Revision}
+		for i, r := range usages {
+			sort.Strings(r.Files)
+			r.Behind = i
+			group.Revisions = append(group.Revisions, r)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// revisionKey returns the same key for different spellings of the same version
+func revisionKey(r module.Revision) string {
+	if v, ok := r.Version(); ok {
+		return fmt.Sprintf("%d.%d.%d-%s", v.Major, v.Minor, v.Patch, v.Prerelease)
+	}
+
+	// prefix keeps revisions which are not versions apart from version keys
+	return "revision:" + string(r)
+}
+
+// MaxBehind returns how many revisions the oldest revision of the group is behind the newest one
+func (g DriftGroup) MaxBehind() int {
+	if len(g.Revisions) == 0 {
+		return 0
+	}
+
+	return g.Revisions[len(g.Revisions)-1].Behind
+}
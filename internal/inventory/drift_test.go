@@ -0,0 +1,101 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func TestDrift(t *testing.T) {
+	testCases := []struct {
+		name           string
+		calls          []updater.ModuleCall
+		expectedResult []DriftGroup
+	}{
+		{
+			name: "single revision is not a drift",
+			calls: []updater.ModuleCall{
+				{File: "/a/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"},
+				{File: "/b/main.tf", Source: "github.com/example-org/modules//vpc?ref=v1.0.0"},
+			},
+			expectedResult: []DriftGroup{},
+		},
+		{
+			name: "different spellings of the same module are grouped",
+			calls: []updater.ModuleCall{
+				{File: "/a/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=v1.10.0"},
+				{File: "/b/main.tf", Source: "github.com/example-org/modules//vpc/?ref=v1.2.0"},
+				{File: "/c/main.tf", Source: "git::ssh://git@github.com/example-org/modules.git//vpc?ref=v1.2.0"},
+				{File: "/d/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=v1.9.0"},
+				{File: "/d/main.tf", Source: "git::https://github.com/example-org/modules.git//db?ref=v1.9.0"},
+				{File: "/d/main.tf", Source: "./modules/vpc"},
+			},
+			expectedResult: []DriftGroup{
+				{
+					Key:    "github.com/example-org/modules//vpc",
+					Newest: module.Revision("v1.10.0"),
+					Revisions: []RevisionUsage{
+						{Revision: module.Revision("v1.10.0"), Behind: 0, Files: []string{"/a/main.tf"}},
+						{Revision: module.Revision("v1.9.0"), Behind: 1, Files: []string{"/d/main.tf"}},
+						{Revision: module.Revision("v1.2.0"), Behind: 2, Files: []string{"/b/main.tf", "/c/main.tf"}},
+					},
+				},
+			},
+		},
+		{
+			name: "spellings of the same version are one revision",
+			calls: []updater.ModuleCall{
+				{File: "/a/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"},
+				{File: "/b/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=1.0.0"},
+				{File: "/c/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=v1"},
+			},
+			expectedResult: []DriftGroup{},
+		},
+		{
+			name: "spellings of the same version do not inflate behind",
+			calls: []updater.ModuleCall{
+				{File: "/a/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0"},
+				{File: "/b/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"},
+				{File: "/c/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=1.0.0"},
+				{File: "/d/main.tf", Source: "git::https://github.com/example-org/modules.git//vpc?ref=main"},
+			},
+			expectedResult: []DriftGroup{
+				{
+					Key:    "github.com/example-org/modules//vpc",
+					Newest: module.Revision("v1.1.0"),
+					Revisions: []RevisionUsage{
+						{Revision: module.Revision("v1.1.0"), Behind: 0, Files: []string{"/a/main.tf"}},
+						{Revision: module.Revision("1.0.0"), Behind: 1, Files: []string{"/b/main.tf", "/c/main.tf"}},
+						{Revision: module.Revision("main"), Behind: 2, Files: []string{"/d/main.tf"}},
+					},
+				},
+			},
+		},
+		{
+			name: "registry version attribute",
+			calls: []updater.ModuleCall{
+				{File: "/a/main.tf", Source: "terraform-aws-modules/vpc/aws", Version: "3.0.0"},
+				{File: "/b/main.tf", Source: "terraform-aws-modules/vpc/aws", Version: "3.1.0"},
+			},
+			expectedResult: []DriftGroup{
+				{
+					Key:    "terraform-aws-modules/vpc/aws",
+					Newest: module.Revision("3.1.0"),
+					Revisions: []RevisionUsage{
+						{Revision: module.Revision("3.1.0"), Behind: 0, Files: []string{"/b/main.tf"}},
+						{Revision: module.Revision("3.0.0"), Behind: 1, Files: []string{"/a/main.tf"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			assert.Equal(tc.expectedResult, Drift(NewUsages(tc.calls)))
+		})
+	}
+}
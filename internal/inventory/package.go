@@ -0,0 +1,35 @@
+package inventory
+
+import (
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// Usage is a module call with parsed source
+type Usage struct {
+	Call     updater.ModuleCall
+	Source   module.Source
+	Revision module.Revision
+}
+
+// DriftGroup holds all revisions of the same repository and submodule used across a tree
+type DriftGroup struct {
+	// Key is canonical repository with submodule, e.g. github.com/example-org/modules//vpc
+	Key string `json:"key"`
+
+	// Newest is the newest revision used in the tree
+	Newest module.Revision `json:"newest"`
+
+	// Revisions are ordered from the newest to the oldest
+	Revisions []RevisionUsage `json:"revisions"`
+}
+
+// RevisionUsage lists files which use particular revision
+type RevisionUsage struct {
+	Revision module.Revision `json:"revision"`
+
+	// Behind is the number of newer revisions used in the tree, 0 for the newest one
+	Behind int `json:"behind"`
+
+	Files []string `json:"files"`
+}
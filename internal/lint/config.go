@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// DefaultConfigFile is the name of configuration file looked up in the current directory
+const DefaultConfigFile = ".tf-module-update.json"
+
+// Config holds lint configuration, usually loaded from JSON file
+type Config struct {
+	// Rules changes settings of rules by their IDs
+	Rules map[string]RuleConfig `json:"rules"`
+
+	// Directories changes settings of rules for files in particular directories,
+	// relative paths are resolved against BaseDir
+	Directories map[string]DirectoryConfig `json:"directories"`
+
+	AllowedHosts    []string `json:"allowed_hosts"`
+	DeniedRevisions []string `json:"denied_revisions"`
+	BranchNames     []string `json:"branch_names"`
+
+	// BaseDir is the directory of configuration file
+	BaseDir string `json:"-"`
+}
+
+// RuleConfig enables or disables the rule and overrides its severity, empty fields are not changed
+type RuleConfig struct {
+	Enabled  *bool  `json:"enabled"`
+	Severity string `json:"severity"`
+}
+
+// DirectoryConfig holds settings of rules for a directory and all its subdirectories
+type DirectoryConfig struct {
+	Rules map[string]RuleConfig `json:"rules"`
+}
+
+// LoadConfig reads configuration from JSON file
+func LoadConfig(path string) (Config, error) {
+	config := Config{}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return config, err
+	}
+
+	content, err := ioutil.ReadFile(absPath)
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(content, &config); err != nil {
+		return config, fmt.Errorf("cannot parse lint configuration %s: %s", path, err)
+	}
+	config.BaseDir = filepath.Dir(absPath)
+
+	ruleSets := []map[string]RuleConfig{config.Rules}
+	for _, d := range config.Directories {
+		ruleSets = append(ruleSets, d.Rules)
+	}
+	for _, rules := range ruleSets {
+		for id, r := range rules {
+			if _, err := r.severity(updater.LevelError); err != nil {
+				return config, fmt.Errorf("rule %s: %s", id, err)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// settings returns if the rule is enabled and its severity for the given file
+//
+// Settings of more specific directories take precedence
+func (c Config) settings(rule Rule, file string) (bool, updater.Level) {
+	enabled := true
+	severity := rule.Severity()
+	apply := func(r RuleConfig, ok bool) {
+		if !ok {
+			return
+		}
+		if r.Enabled != nil {
+			enabled = *r.Enabled
+		}
+		severity, _ = r.severity(severity)
+	}
+
+	r, ok := c.Rules[rule.ID()]
+	apply(r, ok)
+
+	dirs := make([]string, 0, len(c.Directories))
+	for d := range c.Directories {
+		dirs = append(dirs, d)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return len(filepath.Clean(dirs[i])) < len(filepath.Clean(dirs[j]))
+	})
+
+	for _, d := range dirs {
+		if !c.contains(d, file) {
+			continue
+		}
+		r, ok := c.Directories[d].Rules[rule.ID()]
+		apply(r, ok)
+	}
+
+	return enabled, severity
+}
+
+// contains reports if the file is located in the directory or any of its subdirectories
+func (c Config) contains(dir, file string) bool {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(c.BaseDir, dir)
+	}
+
+	rel, err := filepath.Rel(filepath.Clean(dir), file)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func (r RuleConfig) severity(fallback updater.Level) (updater.Level, error) {
+	if r.Severity == "" {
+		return fallback, nil
+	}
+
+	level, err := updater.LevelFromString(r.Severity)
+	if err != nil {
+		return fallback, err
+	}
+	if level != updater.LevelWarn && level != updater.LevelError {
+		return fallback, fmt.Errorf("severity must be warn or error but got %s", r.Severity)
+	}
+
+	return level, nil
+}
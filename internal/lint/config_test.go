@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestLoadConfig(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError bool
+	}{
+		{
+			name: "valid configuration",
+			content: `{
+  "rules": {"branch-ref": {"severity": "warn"}},
+  "directories": {"legacy": {"rules": {"missing-ref": {"enabled": false}}}},
+  "allowed_hosts": ["github.com"]
+}`,
+		},
+		{
+			name:          "unknown severity",
+			content:       `{"rules": {"branch-ref": {"severity": "fatal"}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "severity below warn",
+			content:       `{"directories": {"legacy": {"rules": {"branch-ref": {"severity": "info"}}}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "invalid JSON",
+			content:       `{"rules": `,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, DefaultConfigFile)
+			assert.NoError(ioutil.WriteFile(path, []byte(tc.content), 0644))
+
+			config, err := LoadConfig(path)
+			if tc.expectedError {
+				assert.Equal(true, err != nil)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(dir, config.BaseDir)
+		})
+	}
+}
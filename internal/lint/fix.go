@@ -0,0 +1,69 @@
+package lint
+
+import (
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+)
+
+// FixStrategy updates sources by fixing violations of rules which implement Fixer
+//
+// Rules are checked in the linter order and every fix is applied to the result of the previous one
+type FixStrategy struct {
+	linter *Linter
+	file   string
+}
+
+var _ strategies.FileStrategy = (*FixStrategy)(nil)
+var _ strategies.Explainer = (*FixStrategy)(nil)
+
+// ForFile returns strategy which respects rule settings of the given file
+func (s *FixStrategy) ForFile(path string) strategies.Strategy {
+	return &FixStrategy{linter: s.linter, file: path}
+}
+
+// Decide returns true if at least one fixable rule is violated
+func (s *FixStrategy) Decide(source module.Source) bool {
+	_, fixed := s.fix(source)
+	return len(fixed) > 0
+}
+
+// Apply fixes all fixable violations
+func (s *FixStrategy) Apply(source module.Source) module.Source {
+	result, _ := s.fix(source)
+	return result
+}
+
+// Explain lists IDs and descriptions of rules fixed by Apply
+func (s *FixStrategy) Explain(source module.Source) []string {
+	_, fixed := s.fix(source)
+	reasons := []string{}
+	for _, rule := range fixed {
+		reasons = append(reasons, "fixed "+rule.ID()+": "+rule.Description())
+	}
+
+	return reasons
+}
+
+func (s *FixStrategy) fix(source module.Source) (module.Source, []Rule) {
+	fixed := []Rule{}
+	for _, rule := range s.linter.rules {
+		fixer, ok := rule.(Fixer)
+		if !ok {
+			continue
+		}
+
+		if enabled, _ := s.linter.config.settings(rule, s.file); !enabled || !rule.Violated()(source) {
+			continue
+		}
+
+		source = fixer.Fix(source)
+		fixed = append(fixed, rule)
+	}
+
+	return source, fixed
+}
+
+// NewFixStrategy builds strategy which fixes violations of the linter rules
+func NewFixStrategy(linter *Linter) *FixStrategy {
+	return &FixStrategy{linter: linter}
+}
@@ -0,0 +1,80 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+func TestFixStrategy(t *testing.T) {
+	disabled := false
+	testCases := []struct {
+		name            string
+		config          Config
+		file            string
+		source          string
+		expectedDecide  bool
+		expectedSource  string
+		expectedReasons []string
+	}{
+		{
+			name:            "nothing to fix",
+			source:          "git::https://example.com/example-org/modules.git//vpc?ref=v1.0.0",
+			expectedDecide:  false,
+			expectedSource:  "git::https://example.com/example-org/modules.git//vpc?ref=v1.0.0",
+			expectedReasons: []string{},
+		},
+		{
+			name:            "violations without fixes are left as is",
+			source:          "git::https://example.com/example-org/modules.git//vpc?ref=main",
+			expectedDecide:  false,
+			expectedSource:  "git::https://example.com/example-org/modules.git//vpc?ref=main",
+			expectedReasons: []string{},
+		},
+		{
+			name:           "all fixes are combined",
+			source:         "http://example.com/example-org/modules.GIT.git/?ref=v1.0.0",
+			expectedDecide: true,
+			expectedSource: "git::https://example.com/example-org/modules.git?ref=v1.0.0",
+			expectedReasons: []string{
+				"fixed insecure-http: source uses insecure http:// scheme",
+				"fixed missing-git-prefix: git repository on generic host is not marked with git:: prefix",
+				"fixed git-suffix: git repository path should end with a single lowercase .git suffix",
+			},
+		},
+		{
+			name:            "github does not need git prefix",
+			source:          "https://github.com/example-org/modules.git.git//vpc?ref=v1.0.0",
+			expectedDecide:  true,
+			expectedSource:  "https://github.com/example-org/modules.git//vpc?ref=v1.0.0",
+			expectedReasons: []string{"fixed git-suffix: git repository path should end with a single lowercase .git suffix"},
+		},
+		{
+			name: "rules disabled for the directory are not fixed",
+			config: Config{
+				BaseDir:     "/infra",
+				Directories: map[string]DirectoryConfig{"legacy": {Rules: map[string]RuleConfig{RuleInsecureHTTP: {Enabled: &disabled}}}},
+			},
+			file:            "/infra/legacy/main.tf",
+			source:          "git::http://example.com/example-org/modules.git?ref=v1.0.0",
+			expectedDecide:  false,
+			expectedSource:  "git::http://example.com/example-org/modules.git?ref=v1.0.0",
+			expectedReasons: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			source, err := module.ParseSource(tc.source)
+			assert.NoError(err)
+
+			strategy := NewFixStrategy(NewLinter(tc.config)).ForFile(tc.file).(*FixStrategy)
+			assert.Equal(tc.expectedDecide, strategy.Decide(source))
+			assert.Equal(tc.expectedSource, strategy.Apply(source).String())
+			assert.Equal(tc.expectedReasons, strategy.Explain(source))
+		})
+	}
+}
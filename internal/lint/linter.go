@@ -0,0 +1,50 @@
+package lint
+
+import (
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// Linter checks module calls against a set of rules
+type Linter struct {
+	config Config
+	rules  []Rule
+}
+
+// Lint checks each module call against enabled rules and returns findings in the order of calls
+//
+// Sources that cannot be parsed are reported as invalid-source findings
+func (l *Linter) Lint(calls []updater.ModuleCall) []Finding {
+	findings := []Finding{}
+	for _, c := range calls {
+		source, err := module.ParseSource(c.Source)
+		if err != nil {
+			findings = append(findings, Finding{Call: c, RuleID: RuleInvalidSource, Severity: updater.LevelError, Message: err.Error()})
+			continue
+		}
+
+		for _, rule := range l.rules {
+			enabled, severity := l.config.settings(rule, c.File)
+			if !enabled || !rule.Violated()(source) {
+				continue
+			}
+			findings = append(findings, Finding{Call: c, RuleID: rule.ID(), Severity: severity, Message: rule.Description()})
+		}
+	}
+
+	return findings
+}
+
+// Rules returns rules the linter checks
+func (l *Linter) Rules() []Rule {
+	return l.rules
+}
+
+// NewLinter builds linter with the given rules, DefaultRules(config) are used if no rules are given
+func NewLinter(config Config, rules ...Rule) *Linter {
+	if len(rules) == 0 {
+		rules = DefaultRules(config)
+	}
+
+	return &Linter{config: config, rules: rules}
+}
@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func TestLint(t *testing.T) {
+	disabled := false
+	testCases := []struct {
+		name           string
+		config         Config
+		source         string
+		file           string
+		expectedResult []string
+	}{
+		{
+			name:           "pinned https source",
+			source:         "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0",
+			expectedResult: []string{},
+		},
+		{
+			name:           "missing ref",
+			source:         "git::https://github.com/example-org/modules.git//vpc",
+			expectedResult: []string{"missing-ref:ERROR"},
+		},
+		{
+			name:           "registry module without ref is fine",
+			source:         "terraform-aws-modules/vpc/aws",
+			expectedResult: []string{},
+		},
+		{
+			name:           "branch over http",
+			source:         "git::http://example.com/example-org/modules.git//vpc?ref=master",
+			expectedResult: []string{"branch-ref:ERROR", "insecure-http:ERROR"},
+		},
+		{
+			name:           "custom branch names",
+			config:         Config{BranchNames: []string{"release"}},
+			source:         "git::https://example.com/example-org/modules.git?ref=release",
+			expectedResult: []string{"branch-ref:ERROR"},
+		},
+		{
+			name:           "host is not allowed",
+			config:         Config{AllowedHosts: []string{"github.com"}},
+			source:         "git::https://example.com/example-org/modules.git?ref=v1.0.0",
+			expectedResult: []string{"host-not-allowed:ERROR"},
+		},
+		{
+			name:           "local module has no host to check",
+			config:         Config{AllowedHosts: []string{"github.com"}},
+			source:         "./modules/vpc",
+			expectedResult: []string{},
+		},
+		{
+			name:           "denied revision",
+			config:         Config{DeniedRevisions: []string{"v1.3.0"}},
+			source:         "git::https://github.com/example-org/modules.git?ref=v1.3.0",
+			expectedResult: []string{"denied-revision:ERROR"},
+		},
+		{
+			name:           "invalid source",
+			source:         "hg::https://example.com/modules?ref=v1.3.0",
+			expectedResult: []string{"invalid-source:ERROR"},
+		},
+		{
+			name: "rule settings",
+			config: Config{
+				Rules: map[string]RuleConfig{
+					RuleBranchRef:    {Severity: "warn"},
+					RuleInsecureHTTP: {Enabled: &disabled},
+				},
+			},
+			source:         "git::http://example.com/example-org/modules.git?ref=main",
+			expectedResult: []string{"branch-ref:WARN"},
+		},
+		{
+			name: "directory settings",
+			config: Config{
+				BaseDir: "/infra",
+				Directories: map[string]DirectoryConfig{
+					"legacy": {Rules: map[string]RuleConfig{RuleMissingRef: {Enabled: &disabled}}},
+					"/other": {Rules: map[string]RuleConfig{RuleInsecureHTTP: {Enabled: &disabled}}},
+				},
+			},
+			file:           "/infra/legacy/dev/main.tf",
+			source:         "git::http://example.com/example-org/modules.git",
+			expectedResult: []string{"insecure-http:ERROR"},
+		},
+		{
+			name: "directory settings do not apply to siblings",
+			config: Config{
+				BaseDir: "/infra",
+				Directories: map[string]DirectoryConfig{
+					"legacy": {Rules: map[string]RuleConfig{RuleMissingRef: {Enabled: &disabled}}},
+				},
+			},
+			file:           "/infra/legacy-new/main.tf",
+			source:         "git::https://example.com/example-org/modules.git",
+			expectedResult: []string{"missing-ref:ERROR"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			findings := NewLinter(tc.config).Lint([]updater.ModuleCall{{File: tc.file, Source: tc.source}})
+			result := []string{}
+			for _, f := range findings {
+				severity := "WARN"
+				if f.Severity == updater.LevelError {
+					severity = "ERROR"
+				}
+				result = append(result, f.RuleID+":"+severity)
+			}
+			assert.Equal(tc.expectedResult, result)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package lint
+
+import (
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// Rule checks module source against a policy
+type Rule interface {
+	// ID is unique identifier of the rule used in configuration and reports
+	ID() string
+
+	// Description explains what is wrong with the source which violates the rule
+	Description() string
+
+	// Severity is the default severity of findings, WARN or ERROR
+	Severity() updater.Level
+
+	// Violated returns true for sources which break the rule
+	Violated() conditions.Condition
+}
+
+// Fixer is implemented by rules which violations can be fixed automatically
+type Fixer interface {
+	// Fix returns source which does not violate the rule
+	Fix(module.Source) module.Source
+}
+
+// Finding is a single rule violation
+type Finding struct {
+	Call     updater.ModuleCall
+	RuleID   string
+	Severity updater.Level
+	Message  string
+}
@@ -0,0 +1,151 @@
+package lint
+
+import (
+	"regexp"
+
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// Identifiers of built-in rules
+const (
+	RuleMissingRef     = "missing-ref"
+	RuleBranchRef      = "branch-ref"
+	RuleInsecureHTTP   = "insecure-http"
+	RuleHostNotAllowed = "host-not-allowed"
+	RuleDeniedRevision = "denied-revision"
+	RuleInvalidSource  = "invalid-source"
+	RuleGitPrefix      = "missing-git-prefix"
+	RuleGitSuffix      = "git-suffix"
+)
+
+// gitSuffix matches one or more ".git" suffixes in any case with optional trailing slashes
+var gitSuffix = regexp.MustCompile(`(?i)(\.git)+/*$`)
+
+// DefaultBranchNames are revisions considered to be branches when configuration does not list them
+var DefaultBranchNames = []string{"main", "master", "develop", "trunk", "HEAD"}
+
+type conditionRule struct {
+	id          string
+	description string
+	severity    updater.Level
+	violated    conditions.Condition
+}
+
+// NewRule builds a rule from condition which returns true for violating sources
+func NewRule(id, description string, severity updater.Level, violated conditions.Condition) Rule {
+	return &conditionRule{id: id, description: description, severity: severity, violated: violated}
+}
+
+func (r *conditionRule) ID() string {
+	return r.id
+}
+
+func (r *conditionRule) Description() string {
+	return r.description
+}
+
+func (r *conditionRule) Severity() updater.Level {
+	return r.severity
+}
+
+func (r *conditionRule) Violated() conditions.Condition {
+	return r.violated
+}
+
+type fixableRule struct {
+	conditionRule
+	fix strategies.MutatorFunc
+}
+
+// NewFixableRule builds a rule which violations are fixed by the given function
+func NewFixableRule(id, description string, severity updater.Level, violated conditions.Condition, fix strategies.MutatorFunc) Rule {
+	return &fixableRule{
+		conditionRule: conditionRule{id: id, description: description, severity: severity, violated: violated},
+		fix:           fix,
+	}
+}
+
+func (r *fixableRule) Fix(source module.Source) module.Source {
+	return r.fix(source)
+}
+
+// DefaultRules builds built-in rules using lists from configuration
+//
+// Host allow-list rule is added only if the list of allowed hosts is not empty
+func DefaultRules(config Config) []Rule {
+	branches := config.BranchNames
+	if len(branches) == 0 {
+		branches = DefaultBranchNames
+	}
+
+	rules := []Rule{
+		NewRule(RuleMissingRef, "git source is not pinned with ?ref=", updater.LevelError, conditions.All(
+			conditions.KindMatches(module.KindGit),
+			conditions.RevisionMatches(module.Revision("")),
+		)),
+		NewRule(RuleBranchRef, "source references a branch instead of a tag or commit", updater.LevelError, revisionIn(branches)),
+		NewFixableRule(RuleInsecureHTTP, "source uses insecure http:// scheme", updater.LevelError, conditions.SchemeMatches("http"),
+			func(s module.Source) module.Source {
+				s.Scheme = "https"
+				return s
+			},
+		),
+		NewRule(RuleDeniedRevision, "source uses denied revision", updater.LevelError, revisionIn(config.DeniedRevisions)),
+		NewFixableRule(RuleGitPrefix, "git repository on generic host is not marked with git:: prefix", updater.LevelWarn, missingGitPrefix,
+			func(s module.Source) module.Source {
+				s.SpecialPrefix = "git::"
+				return s
+			},
+		),
+		NewFixableRule(RuleGitSuffix, "git repository path should end with a single lowercase .git suffix", updater.LevelWarn, irregularGitSuffix,
+			func(s module.Source) module.Source {
+				s.Module = gitSuffix.ReplaceAllString(s.Module, ".git")
+				return s
+			},
+		),
+	}
+
+	if len(config.AllowedHosts) > 0 {
+		allowed := []conditions.Condition{}
+		for _, h := range config.AllowedHosts {
+			allowed = append(allowed, conditions.HostMatches(h))
+		}
+		rules = append(rules, NewRule(RuleHostNotAllowed, "source host is not in the list of allowed hosts", updater.LevelError, conditions.All(
+			conditions.Not(conditions.HostMatches("")),
+			conditions.Not(conditions.Any(allowed...)),
+		)))
+	}
+
+	return rules
+}
+
+func revisionIn(revisions []string) conditions.Condition {
+	matches := []conditions.Condition{}
+	for _, r := range revisions {
+		matches = append(matches, conditions.RevisionMatches(module.Revision(r)))
+	}
+
+	return conditions.Any(matches...)
+}
+
+// missingGitPrefix is true for http(s) git repositories on hosts Terraform does not recognize as git ones,
+// without the prefix Terraform downloads such sources as plain HTTP archives
+func missingGitPrefix(s module.Source) bool {
+	if s.SpecialPrefix != "" || (s.Scheme != "http" && s.Scheme != "https") || !gitSuffix.MatchString(s.Module) {
+		return false
+	}
+
+	return !conditions.Any(
+		conditions.HostMatches("github.com"),
+		conditions.HostMatches("bitbucket.org"),
+	)(s)
+}
+
+// irregularGitSuffix is true for git repositories with repeated, uppercase or slash terminated ".git" suffix
+func irregularGitSuffix(s module.Source) bool {
+	suffix := gitSuffix.FindString(s.Module)
+	return suffix != "" && suffix != ".git"
+}
@@ -0,0 +1,183 @@
+package lockfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// Build creates lock for git module calls, baseDir is the folder of the lock file
+//
+// Entries of previous lock are kept as long as the source of the call is unchanged,
+// so a tag moved after locking is detected by Verify instead of being silently accepted.
+// Entries of files which were not processed are kept too unless such files are removed
+func Build(calls []updater.ModuleCall, baseDir string, previous Lock, resolver Resolver) (Lock, []error) {
+	lock := Lock{}
+	errs := []error{}
+	processed := map[string]bool{}
+	indexes := callIndexes(calls)
+	for i, c := range calls {
+		name := describe(c.Block, c.Name, indexes[i])
+		source, file, ok, err := lockable(c, name, baseDir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		processed[file] = true
+		if !ok {
+			continue
+		}
+
+		if e, found := previous.Find(file, c.Block, c.Name, indexes[i]); found && e.Source == c.Source {
+			lock.Modules = append(lock.Modules, e)
+			continue
+		}
+
+		commit, err := resolver.ResolveCommit(source)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %s", c.File, name, err))
+			continue
+		}
+
+		lock.Modules = append(lock.Modules, Entry{
+			File:       file,
+			Name:       c.Name,
+			Block:      c.Block,
+			Index:      indexes[i],
+			Repository: source.Repository(),
+			Source:     c.Source,
+			Ref:        string(source.Revision),
+			Commit:     commit,
+		})
+	}
+
+	for _, e := range previous.Modules {
+		if !processed[e.File] && fileExists(baseDir, e.File) {
+			lock.Modules = append(lock.Modules, e)
+		}
+	}
+
+	return lock, errs
+}
+
+// Verify checks that module calls match the lock and that locked refs still point to the locked commits
+//
+// Entries of processed or removed files which do not have a module call anymore are reported as stale
+func Verify(calls []updater.ModuleCall, baseDir string, lock Lock, resolver Resolver) []error {
+	errs := []error{}
+	processed := map[string]bool{}
+	seen := map[Entry]bool{}
+	indexes := callIndexes(calls)
+	for i, c := range calls {
+		name := describe(c.Block, c.Name, indexes[i])
+		source, file, ok, err := lockable(c, name, baseDir)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		processed[file] = true
+		if !ok {
+			continue
+		}
+
+		e, found := lock.Find(file, c.Block, c.Name, indexes[i])
+		if !found {
+			errs = append(errs, fmt.Errorf("%s: %s is not locked", c.File, name))
+			continue
+		}
+		seen[e] = true
+
+		if e.Source != c.Source {
+			errs = append(errs, fmt.Errorf("%s: %s: source %s does not match locked %s", c.File, name, c.Source, e.Source))
+			continue
+		}
+
+		commit, err := resolver.ResolveCommit(source)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %s", c.File, name, err))
+			continue
+		}
+		if commit != e.Commit {
+			errs = append(errs, fmt.Errorf("%s: %s: %s of %s points to %s, locked commit is %s", c.File, name, e.Ref, e.Repository, commit, e.Commit))
+		}
+	}
+
+	for _, e := range lock.Modules {
+		if seen[e] {
+			continue
+		}
+		if processed[e.File] || !fileExists(baseDir, e.File) {
+			errs = append(errs, fmt.Errorf("%s: %s is locked but not called anymore", e.File, describe(e.Block, e.Name, e.Index)))
+		}
+	}
+
+	return errs
+}
+
+// lockable parses source of git module call and returns its file relative to baseDir,
+// calls of other kinds are not locked as they are not pinned to a commit. The name is used in errors
+func lockable(c updater.ModuleCall, name string, baseDir string) (module.Source, string, bool, error) {
+	file, err := relativePath(baseDir, c.File)
+	if err != nil {
+		return module.Source{}, "", false, err
+	}
+
+	source, err := module.ParseSource(c.Source)
+	if err != nil || source.Kind() != module.KindGit {
+		return source, file, false, nil
+	}
+
+	if source.Revision == "" {
+		return source, file, false, fmt.Errorf("%s: %s: source %s is not pinned to a ref, it cannot be locked", c.File, name, c.Source)
+	}
+
+	return source, file, true, nil
+}
+
+// callIndexes numbers calls with the same file, block and name in their order, see Entry.Index
+func callIndexes(calls []updater.ModuleCall) []int {
+	counts := map[[3]string]int{}
+	indexes := make([]int, len(calls))
+	for i, c := range calls {
+		key := [3]string{c.File, c.Block, c.Name}
+		indexes[i] = counts[key]
+		counts[key]++
+	}
+
+	return indexes
+}
+
+// describe names the call in messages, calls without name are told apart by block and number
+func describe(block, name string, index int) string {
+	if name != "" {
+		return fmt.Sprintf("module %q", name)
+	}
+
+	return fmt.Sprintf("%s #%d", block, index+1)
+}
+
+func relativePath(baseDir, file string) (string, error) {
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return "", err
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(absBase, absFile)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+func fileExists(baseDir, file string) bool {
+	_, err := os.Stat(filepath.Join(baseDir, filepath.FromSlash(file)))
+	return err == nil
+}
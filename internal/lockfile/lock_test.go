@@ -0,0 +1,182 @@
+package lockfile
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// mapResolver resolves revisions by "<repository>@<revision>" keys
+type mapResolver map[string]string
+
+func (r mapResolver) ResolveCommit(s module.Source) (string, error) {
+	commit, ok := r[s.Repository()+"@"+string(s.Revision)]
+	if !ok {
+		return "", errors.New("unknown revision " + string(s.Revision))
+	}
+
+	return commit, nil
+}
+
+// newTestTree creates files in a temporary folder, so removed files can be told apart from unprocessed ones
+func newTestTree(t *testing.T, files ...string) string {
+	dir := t.TempDir()
+	for _, f := range files {
+		name := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func errorStrings(errs []error) []string {
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return messages
+}
+
+func TestBuild(t *testing.T) {
+	dir := newTestTree(t, "prod/main.tf", "dev/main.tf", "stage/main.tf")
+	resolver := mapResolver{
+		"github.com/org/modules@v1": "c1",
+		"github.com/org/modules@v2": "c2",
+	}
+	previous := Lock{Modules: []Entry{
+		{File: "prod/main.tf", Name: "vpc", Repository: "github.com/org/modules", Source: "git::https://github.com/org/modules.git//vpc?ref=v1", Ref: "v1", Commit: "locked"},
+		{File: "prod/main.tf", Name: "removed", Repository: "github.com/org/modules", Source: "git::https://github.com/org/modules.git?ref=v1", Ref: "v1", Commit: "c1"},
+		{File: "stage/main.tf", Name: "vpc", Repository: "github.com/org/modules", Source: "git::https://github.com/org/modules.git?ref=v1", Ref: "v1", Commit: "c1"},
+		{File: "deleted/main.tf", Name: "vpc", Repository: "github.com/org/modules", Source: "git::https://github.com/org/modules.git?ref=v1", Ref: "v1", Commit: "c1"},
+	}}
+	calls := []updater.ModuleCall{
+		{File: filepath.Join(dir, "prod", "main.tf"), Name: "vpc", Source: "git::https://github.com/org/modules.git//vpc?ref=v1"},
+		{File: filepath.Join(dir, "dev", "main.tf"), Name: "vpc", Source: "git::https://github.com/org/modules.git//vpc?ref=v2"},
+		{File: filepath.Join(dir, "dev", "main.tf"), Name: "registry", Source: "terraform-aws-modules/vpc/aws", Version: "3.0.0"},
+		{File: filepath.Join(dir, "dev", "main.tf"), Name: "local", Source: "./modules/vpc"},
+		{File: filepath.Join(dir, "dev", "main.tf"), Name: "unpinned", Source: "git::https://github.com/org/modules.git"},
+		{File: filepath.Join(dir, "dev", "main.tf"), Name: "unknown", Source: "git::https://github.com/org/modules.git?ref=v3"},
+	}
+
+	assert := testhelpers.Assert(t)
+	lock, errs := Build(calls, dir, previous, resolver)
+	assert.Equal([]Entry{
+		// unchanged source keeps locked commit
+		previous.Modules[0],
+		{File: "dev/main.tf", Name: "vpc", Repository: "github.com/org/modules", Source: "git::https://github.com/org/modules.git//vpc?ref=v2", Ref: "v2", Commit: "c2"},
+		// files which were not processed keep their entries
+		previous.Modules[2],
+	}, lock.Modules)
+	assert.Equal(2, len(errs))
+	assert.Equal(true, strings.Contains(errs[0].Error(), `module "unpinned": source git::https://github.com/org/modules.git is not pinned to a ref`))
+	assert.Equal(true, strings.Contains(errs[1].Error(), `module "unknown": unknown revision v3`))
+}
+
+func TestVerify(t *testing.T) {
+	dir := newTestTree(t, "main.tf", "other.tf")
+	file := filepath.Join(dir, "main.tf")
+	entry := func(name, ref, commit string) Entry {
+		return Entry{
+			File:       "main.tf",
+			Name:       name,
+			Repository: "github.com/org/modules",
+			Source:     "git::https://github.com/org/modules.git?ref=" + ref,
+			Ref:        ref,
+			Commit:     commit,
+		}
+	}
+	call := func(name, ref string) updater.ModuleCall {
+		return updater.ModuleCall{File: file, Name: name, Source: "git::https://github.com/org/modules.git?ref=" + ref}
+	}
+	resolver := mapResolver{
+		"github.com/org/modules@v1": "c1",
+		"github.com/org/modules@v2": "moved",
+	}
+
+	tests := []struct {
+		name     string
+		calls    []updater.ModuleCall
+		lock     Lock
+		expected []string
+	}{
+		{
+			name:     "match",
+			calls:    []updater.ModuleCall{call("vpc", "v1"), {File: file, Name: "registry", Source: "terraform-aws-modules/vpc/aws"}},
+			lock:     Lock{Modules: []Entry{entry("vpc", "v1", "c1")}},
+			expected: []string{},
+		},
+		{
+			name:     "not locked",
+			calls:    []updater.ModuleCall{call("vpc", "v1")},
+			lock:     Lock{},
+			expected: []string{file + `: module "vpc" is not locked`},
+		},
+		{
+			name:     "source changed",
+			calls:    []updater.ModuleCall{call("vpc", "v2")},
+			lock:     Lock{Modules: []Entry{entry("vpc", "v1", "c1")}},
+			expected: []string{file + `: module "vpc": source git::https://github.com/org/modules.git?ref=v2 does not match locked git::https://github.com/org/modules.git?ref=v1`},
+		},
+		{
+			name:     "tag moved",
+			calls:    []updater.ModuleCall{call("vpc", "v2")},
+			lock:     Lock{Modules: []Entry{entry("vpc", "v2", "c2")}},
+			expected: []string{file + `: module "vpc": v2 of github.com/org/modules points to moved, locked commit is c2`},
+		},
+		{
+			name:     "ref removed",
+			calls:    []updater.ModuleCall{call("vpc", "v3")},
+			lock:     Lock{Modules: []Entry{entry("vpc", "v3", "c3")}},
+			expected: []string{file + `: module "vpc": unknown revision v3`},
+		},
+		{
+			name:  "stale entries",
+			calls: []updater.ModuleCall{call("vpc", "v1")},
+			lock: Lock{Modules: []Entry{
+				entry("vpc", "v1", "c1"),
+				entry("removed", "v1", "c1"),
+				{File: "other.tf", Name: "unprocessed"},
+				{File: "deleted.tf", Name: "vpc"},
+			}},
+			expected: []string{
+				`main.tf: module "removed" is locked but not called anymore`,
+				`deleted.tf: module "vpc" is locked but not called anymore`,
+			},
+		},
+		{
+			name: "run modules",
+			calls: []updater.ModuleCall{
+				{File: file, Block: "run.module", Source: "git::https://github.com/org/modules.git?ref=v1"},
+				{File: file, Block: "run.module", Source: "git::https://github.com/org/modules.git?ref=v2"},
+			},
+			lock: Lock{Modules: []Entry{
+				{File: "main.tf", Block: "run.module", Repository: "github.com/org/modules", Source: "git::https://github.com/org/modules.git?ref=v1", Ref: "v1", Commit: "c1"},
+				{File: "main.tf", Block: "run.module", Index: 2, Repository: "github.com/org/modules", Source: "git::https://github.com/org/modules.git?ref=v1", Ref: "v1", Commit: "c1"},
+			}},
+			expected: []string{
+				file + `: run.module #2 is not locked`,
+				`main.tf: run.module #3 is locked but not called anymore`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Verify(tt.calls, dir, tt.lock, resolver)
+			testhelpers.Assert(t).Equal(tt.expected, errorStrings(errs))
+		})
+	}
+}
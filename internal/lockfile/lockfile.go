@@ -0,0 +1,132 @@
+package lockfile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/maxim-nazarenko/tf-module-update/internal/fileutil"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// moduleBlock is the block of calls whose entries are written with file and name labels only
+const moduleBlock = "module"
+
+const header = `# This file is maintained automatically by "tf-module-update lock".
+# Manual edits may be lost in future updates.
+`
+
+// Find returns the entry of module call, see Entry for the meaning of arguments
+func (l Lock) Find(file, block, name string, index int) (Entry, bool) {
+	for _, e := range l.Modules {
+		if e.File == file && e.Block == block && e.Name == name && e.Index == index {
+			return e, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// Bytes renders the lock file, entries are sorted by file, block, name and index so the output is stable
+//
+// Entries of "module" blocks are labelled with file and name, entries of other blocks
+// are labelled with file, name, block and index
+func (l Lock) Bytes() []byte {
+	entries := append([]Entry{}, l.Modules...)
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].File != entries[j].File {
+			return entries[i].File < entries[j].File
+		}
+		if entries[i].Block != entries[j].Block {
+			return entries[i].Block < entries[j].Block
+		}
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Index < entries[j].Index
+	})
+
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	body.AppendUnstructuredTokens(hclwrite.Tokens{
+		{Type: hclsyntax.TokenComment, Bytes: []byte(header)},
+	})
+	for _, e := range entries {
+		body.AppendNewline()
+		labels := []string{e.File, e.Name}
+		if e.Block != moduleBlock || e.Index != 0 {
+			labels = append(labels, e.Block, strconv.Itoa(e.Index))
+		}
+		block := body.AppendNewBlock("module", labels)
+		block.Body().SetAttributeValue("repository", cty.StringVal(e.Repository))
+		block.Body().SetAttributeValue("source", cty.StringVal(e.Source))
+		block.Body().SetAttributeValue("ref", cty.StringVal(e.Ref))
+		block.Body().SetAttributeValue("commit", cty.StringVal(e.Commit))
+	}
+
+	return hclwrite.Format(f.Bytes())
+}
+
+// Save writes the lock file replacing the previous one
+func (l Lock) Save(path string) error {
+	return fileutil.WriteFileAtomic(path, l.Bytes())
+}
+
+// Parse reads lock file content, filename is used in error messages only
+func Parse(src []byte, filename string) (Lock, error) {
+	lock := Lock{}
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return lock, fmt.Errorf("parsing lock file failed: %s", diags.Error())
+	}
+
+	body := file.Body.(*hclsyntax.Body)
+	for _, block := range body.Blocks {
+		if block.Type != "module" || (len(block.Labels) != 2 && len(block.Labels) != 4) {
+			return lock, fmt.Errorf("%s: unexpected block %s, only module blocks with file and name labels, optionally followed by block and index, are supported", block.DefRange(), block.Type)
+		}
+
+		entry := Entry{File: block.Labels[0], Name: block.Labels[1], Block: moduleBlock}
+		if len(block.Labels) == 4 {
+			index, err := strconv.Atoi(block.Labels[3])
+			if err != nil || index < 0 {
+				return lock, fmt.Errorf("%s: index must be a non-negative number but got %q", block.DefRange(), block.Labels[3])
+			}
+			entry.Block, entry.Index = block.Labels[2], index
+		}
+		for name, target := range map[string]*string{
+			"repository": &entry.Repository,
+			"source":     &entry.Source,
+			"ref":        &entry.Ref,
+			"commit":     &entry.Commit,
+		} {
+			attr, ok := block.Body.Attributes[name]
+			if !ok {
+				return lock, fmt.Errorf("%s: %s is missing %s attribute", block.DefRange(), describe(entry.Block, entry.Name, entry.Index), name)
+			}
+			value, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() || value.Type() != cty.String || value.IsNull() {
+				return lock, fmt.Errorf("%s: %s must be a string", attr.NameRange, name)
+			}
+			*target = value.AsString()
+		}
+
+		lock.Modules = append(lock.Modules, entry)
+	}
+
+	return lock, nil
+}
+
+// Load reads the lock file from path, error satisfies os.IsNotExist if there is no such file
+func Load(path string) (Lock, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Lock{}, err
+	}
+
+	return Parse(src, path)
+}
@@ -0,0 +1,119 @@
+package lockfile
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestLockBytes(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	lock := Lock{Modules: []Entry{
+		{
+			File:       "prod/main.tf",
+			Name:       "vpc",
+			Block:      "module",
+			Repository: "github.com/org/modules",
+			Source:     "git::https://github.com/org/modules.git//vpc?ref=v1.2.0",
+			Ref:        "v1.2.0",
+			Commit:     "0123456789abcdef0123456789abcdef01234567",
+		},
+		{
+			File:       "dev/main.tf",
+			Name:       "vpc",
+			Block:      "module",
+			Repository: "github.com/org/modules",
+			Source:     "git::https://github.com/org/modules.git//vpc?ref=v1.3.0",
+			Ref:        "v1.3.0",
+			Commit:     "89abcdef0123456789abcdef0123456789abcdef",
+		},
+		{
+			File:       "tests/vpc.tftest.hcl",
+			Block:      "run.module",
+			Index:      1,
+			Repository: "github.com/org/modules",
+			Source:     "git::https://github.com/org/modules.git//vpc?ref=v1.3.0",
+			Ref:        "v1.3.0",
+			Commit:     "89abcdef0123456789abcdef0123456789abcdef",
+		},
+	}}
+
+	expected := `# This file is maintained automatically by "tf-module-update lock".
+# Manual edits may be lost in future updates.
+
+module "dev/main.tf" "vpc" {
+  repository = "github.com/org/modules"
+  source     = "git::https://github.com/org/modules.git//vpc?ref=v1.3.0"
+  ref        = "v1.3.0"
+  commit     = "89abcdef0123456789abcdef0123456789abcdef"
+}
+
+module "prod/main.tf" "vpc" {
+  repository = "github.com/org/modules"
+  source     = "git::https://github.com/org/modules.git//vpc?ref=v1.2.0"
+  ref        = "v1.2.0"
+  commit     = "0123456789abcdef0123456789abcdef01234567"
+}
+
+module "tests/vpc.tftest.hcl" "" "run.module" "1" {
+  repository = "github.com/org/modules"
+  source     = "git::https://github.com/org/modules.git//vpc?ref=v1.3.0"
+  ref        = "v1.3.0"
+  commit     = "89abcdef0123456789abcdef0123456789abcdef"
+}
+`
+	assert.Equal(expected, string(lock.Bytes()))
+
+	parsed, err := Parse(lock.Bytes(), DefaultFile)
+	assert.NoError(err)
+	assert.Equal([]Entry{lock.Modules[1], lock.Modules[0], lock.Modules[2]}, parsed.Modules)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{
+			name: "invalid syntax",
+			src:  `module "main.tf" "vpc" {`,
+		},
+		{
+			name: "unexpected block",
+			src:  `provider "aws" {}`,
+		},
+		{
+			name: "missing attribute",
+			src: `module "main.tf" "vpc" {
+  repository = "github.com/org/modules"
+  source     = "git::https://github.com/org/modules.git?ref=v1"
+  ref        = "v1"
+}`,
+		},
+		{
+			name: "invalid index",
+			src: `module "main.tftest.hcl" "" "run.module" "first" {
+  repository = "github.com/org/modules"
+  source     = "git::https://github.com/org/modules.git?ref=v1"
+  ref        = "v1"
+  commit     = "c1"
+}`,
+		},
+		{
+			name: "not a string",
+			src: `module "main.tf" "vpc" {
+  repository = "github.com/org/modules"
+  source     = "git::https://github.com/org/modules.git?ref=v1"
+  ref        = "v1"
+  commit     = 1
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.src), DefaultFile)
+			testhelpers.Assert(t).Equal(true, err != nil)
+		})
+	}
+}
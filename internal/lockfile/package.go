@@ -0,0 +1,44 @@
+package lockfile
+
+import "github.com/maxim-nazarenko/tf-module-update/module"
+
+// DefaultFile is the name of the lock file looked up in the current folder
+const DefaultFile = "tf-modules.lock.hcl"
+
+// Lock records resolved sources of git module calls
+type Lock struct {
+	Modules []Entry
+}
+
+// Entry is the resolved source of a single module call
+type Entry struct {
+	// File is the slash separated path of the file with the call, relative to the folder of the lock file
+	File string
+
+	// Name is the label of module block, empty for blocks without labels, e.g. "module" of "run" block
+	Name string
+
+	// Block is the path of block types to the call, e.g. "module" or "run.module"
+	Block string
+
+	// Index tells apart calls with the same file, block and name by their order in the file,
+	// e.g. modules of several "run" blocks of a test file
+	Index int
+
+	// Repository is the canonical address of the module repository
+	Repository string
+
+	// Source is the module source as it was written in the file when locked
+	Source string
+
+	// Ref is the revision of the source, e.g. a tag
+	Ref string
+
+	// Commit is the full hash of the commit the ref pointed to when locked
+	Commit string
+}
+
+// Resolver finds the commit which revision of a module source points to
+type Resolver interface {
+	ResolveCommit(s module.Source) (string, error)
+}
@@ -0,0 +1,314 @@
+package mirror
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/changelog"
+	"github.com/maxim-nazarenko/tf-module-update/internal/compat"
+	"github.com/maxim-nazarenko/tf-module-update/internal/git"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// Mirror locates local copies of module repositories
+//
+// Repositories are expected in <root>/<host>/<path>, with or without ".git" suffix,
+// e.g. github.com/org/modules.git for https://github.com/org/modules.git.
+// Sources with file:// scheme point to local repositories themselves and do not need the root
+type Mirror struct {
+	root string
+
+	// validated caches results of Validate by source, as the same source is usually used by many calls
+	validated sync.Map
+
+	// changelogs caches results of Changelog by the pair of sources
+	changelogs sync.Map
+
+	// interfaces caches results of moduleInterface by source
+	interfaces sync.Map
+
+	// releases caches results of Releases by repository folder
+	releases sync.Map
+}
+
+// releasesResult is a cached result of Releases
+type releasesResult struct {
+	releases []strategies.Release
+	err      error
+}
+
+// interfaceResult is a cached result of moduleInterface
+type interfaceResult struct {
+	moduleInterface compat.Interface
+	err             error
+}
+
+// changelogResult is a cached result of Changelog
+type changelogResult struct {
+	changelog updater.Changelog
+	err       error
+}
+
+// Dir returns folder of the local repository with the module
+func (m *Mirror) Dir(s module.Source) (string, error) {
+	if s.Kind() != module.KindGit {
+		return "", fmt.Errorf("%s is not a git module source", s)
+	}
+
+	if s.Scheme == "file" {
+		return filepath.FromSlash(s.Module), nil
+	}
+
+	if m.root == "" {
+		return "", errors.New("no mirror folder is given for " + s.Repository())
+	}
+
+	base := filepath.Join(m.root, filepath.FromSlash(s.Repository()))
+	for _, dir := range []string{base + ".git", base} {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+
+	return "", fmt.Errorf("repository %s is not found in mirror %s", s.Repository(), m.root)
+}
+
+// Open opens the local repository with the module
+func (m *Mirror) Open(s module.Source) (*git.Repository, error) {
+	dir, err := m.Dir(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return git.OpenDir(dir)
+}
+
+// ResolveCommit returns hash of the commit which revision of the source points to
+func (m *Mirror) ResolveCommit(s module.Source) (string, error) {
+	if s.Revision == "" {
+		return "", fmt.Errorf("%s is not pinned to a ref", s)
+	}
+
+	repository, err := m.Open(s)
+	if err != nil {
+		return "", err
+	}
+
+	return repository.ResolveCommit(string(s.Revision))
+}
+
+// Validate checks that revision of the source exists in the repository and the submodule is a folder at it,
+// sources without revision are checked against HEAD. Sources which are not git ones, e.g. registry modules, are not validated
+func (m *Mirror) Validate(s module.Source) error {
+	if s.Kind() != module.KindGit {
+		return nil
+	}
+
+	key := s.String()
+	if v, ok := m.validated.Load(key); ok {
+		err, _ := v.(error)
+		return err
+	}
+
+	err := m.validate(s)
+	m.validated.Store(key, err)
+
+	return err
+}
+
+func (m *Mirror) validate(s module.Source) error {
+	repository, err := m.Open(s)
+	if err != nil {
+		return err
+	}
+
+	ref := string(s.Revision)
+	if ref == "" {
+		ref = "HEAD"
+	}
+	commit, err := repository.ResolveCommit(ref)
+	if err != nil {
+		return fmt.Errorf("ref %s does not exist in %s", ref, s.Repository())
+	}
+
+	submodule := strings.Trim(s.Submodule, "/")
+	if submodule != "" && !repository.IsDir(commit, submodule) {
+		return fmt.Errorf("submodule %s does not exist in %s at %s", submodule, s.Repository(), ref)
+	}
+
+	return nil
+}
+
+// Changelog returns subjects of commits touching the submodule between revisions of the sources
+// and sections of CHANGELOG.md, from the submodule folder or the repository root, for versions in between
+//
+// Nothing is returned when the sources belong to different repositories
+func (m *Mirror) Changelog(before, after module.Source) (updater.Changelog, error) {
+	key := before.String() + " " + after.String()
+	if v, ok := m.changelogs.Load(key); ok {
+		result := v.(changelogResult)
+		return result.changelog, result.err
+	}
+
+	result := changelogResult{}
+	result.changelog, result.err = m.changelog(before, after)
+	m.changelogs.Store(key, result)
+
+	return result.changelog, result.err
+}
+
+func (m *Mirror) changelog(before, after module.Source) (updater.Changelog, error) {
+	if before.Repository() != after.Repository() {
+		return updater.Changelog{}, nil
+	}
+	if before.Revision == "" || after.Revision == "" {
+		return updater.Changelog{}, errors.New("both sources have to be pinned to a ref")
+	}
+
+	repository, err := m.Open(after)
+	if err != nil {
+		return updater.Changelog{}, err
+	}
+	from, err := repository.ResolveCommit(string(before.Revision))
+	if err != nil {
+		return updater.Changelog{}, err
+	}
+	to, err := repository.ResolveCommit(string(after.Revision))
+	if err != nil {
+		return updater.Changelog{}, err
+	}
+
+	submodule := strings.Trim(after.Submodule, "/")
+	commits, err := repository.Log(from, to, submodule)
+	if err != nil {
+		return updater.Changelog{}, err
+	}
+
+	result := updater.Changelog{Commits: commits}
+	for _, name := range []string{path.Join(submodule, changelog.FileName), changelog.FileName} {
+		if content, err := repository.ReadFile(to, name); err == nil {
+			result.Notes = changelog.Sections(content, before.Revision, after.Revision)
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// CheckInterface compares variables and outputs declared in *.tf files of the submodule folder
+// at revisions of the change and checks the differences against arguments of the call
+func (m *Mirror) CheckInterface(c updater.Change) ([]updater.InterfaceFinding, error) {
+	old, err := m.moduleInterface(c.Before)
+	if err != nil {
+		return nil, err
+	}
+	new, err := m.moduleInterface(c.After)
+	if err != nil {
+		return nil, err
+	}
+
+	return compat.Check(old, new, c.Call.Arguments), nil
+}
+
+func (m *Mirror) moduleInterface(s module.Source) (compat.Interface, error) {
+	key := s.String()
+	if v, ok := m.interfaces.Load(key); ok {
+		result := v.(interfaceResult)
+		return result.moduleInterface, result.err
+	}
+
+	result := interfaceResult{}
+	result.moduleInterface, result.err = m.readInterface(s)
+	m.interfaces.Store(key, result)
+
+	return result.moduleInterface, result.err
+}
+
+func (m *Mirror) readInterface(s module.Source) (compat.Interface, error) {
+	repository, err := m.Open(s)
+	if err != nil {
+		return compat.Interface{}, err
+	}
+
+	ref := string(s.Revision)
+	if ref == "" {
+		ref = "HEAD"
+	}
+	commit, err := repository.ResolveCommit(ref)
+	if err != nil {
+		return compat.Interface{}, err
+	}
+
+	names, err := repository.ListFiles(commit, strings.Trim(s.Submodule, "/"))
+	if err != nil {
+		return compat.Interface{}, err
+	}
+
+	files := map[string][]byte{}
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".tf") && !strings.HasSuffix(name, ".tf.json") {
+			continue
+		}
+		content, err := repository.ReadFile(commit, name)
+		if err != nil {
+			return compat.Interface{}, err
+		}
+		files[name] = []byte(content)
+	}
+
+	moduleInterface, err := compat.ParseInterface(files)
+	if err != nil {
+		return compat.Interface{}, fmt.Errorf("%s at %s: %s", s.Repository(), ref, err)
+	}
+
+	return moduleInterface, nil
+}
+
+// Releases returns tags of the source repository with their dates
+func (m *Mirror) Releases(s module.Source) ([]strategies.Release, error) {
+	dir, err := m.Dir(s)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := m.releases.Load(dir); ok {
+		result := v.(releasesResult)
+		return result.releases, result.err
+	}
+
+	result := releasesResult{}
+	result.releases, result.err = m.readReleases(dir)
+	m.releases.Store(dir, result)
+
+	return result.releases, result.err
+}
+
+func (m *Mirror) readReleases(dir string) ([]strategies.Release, error) {
+	repository, err := git.OpenDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := repository.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]strategies.Release, 0, len(tags))
+	for _, t := range tags {
+		releases = append(releases, strategies.Release{Revision: module.Revision(t.Name), Date: t.Date})
+	}
+
+	return releases, nil
+}
+
+// New creates mirror with repositories in the root folder, root might be empty if only file:// sources are used
+func New(root string) *Mirror {
+	return &Mirror{root: root}
+}
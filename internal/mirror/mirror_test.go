@@ -0,0 +1,296 @@
+package mirror
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func TestDir(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"github.com/org/modules.git", "gitlab.com/org/network"} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		root     string
+		source   string
+		expected string
+		isError  bool
+	}{
+		{
+			name:     "bare repository",
+			root:     root,
+			source:   "git::https://github.com/org/modules.git//vpc?ref=v1.0.0",
+			expected: filepath.Join(root, "github.com", "org", "modules.git"),
+		},
+		{
+			name:     "host is case insensitive",
+			root:     root,
+			source:   "git::https://GitHub.com/org/modules//vpc?ref=v1.0.0",
+			expected: filepath.Join(root, "github.com", "org", "modules.git"),
+		},
+		{
+			name:     "working tree",
+			root:     root,
+			source:   "git::ssh://git@gitlab.com/org/network.git?ref=v2",
+			expected: filepath.Join(root, "gitlab.com", "org", "network"),
+		},
+		{
+			name:     "file scheme",
+			source:   "git::file:///srv/git/modules.git//vpc?ref=v1",
+			expected: filepath.FromSlash("/srv/git/modules.git"),
+		},
+		{
+			name:    "missing repository",
+			root:    root,
+			source:  "git::https://github.com/org/other.git?ref=v1",
+			isError: true,
+		},
+		{
+			name:    "no root",
+			source:  "git::https://github.com/org/modules.git?ref=v1",
+			isError: true,
+		},
+		{
+			name:    "registry module",
+			root:    root,
+			source:  "terraform-aws-modules/vpc/aws",
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			source, err := module.ParseSource(tt.source)
+			assert.NoError(err)
+
+			dir, err := New(tt.root).Dir(source)
+			assert.Equal(tt.isError, err != nil)
+			assert.Equal(tt.expected, dir)
+		})
+	}
+}
+
+// newTestRepository creates a repository with "vpc" folder tagged as v1.0.0
+func newTestRepository(t *testing.T) string {
+	dir := testhelpers.NewGitRepository(t, "vpc/main.tf", "")
+	testhelpers.RunGit(t, dir, "tag", "v1.0.0")
+
+	return dir
+}
+
+func TestResolveCommit(t *testing.T) {
+	dir := newTestRepository(t)
+	assert := testhelpers.Assert(t)
+	head := testhelpers.RunGit(t, dir, "rev-parse", "HEAD")
+
+	source, err := module.ParseSource("git::file://" + filepath.ToSlash(dir) + "?ref=v1.0.0")
+	assert.NoError(err)
+	commit, err := New("").ResolveCommit(source)
+	assert.NoError(err)
+	assert.Equal(head, commit)
+
+	source.Revision = ""
+	_, err = New("").ResolveCommit(source)
+	assert.Equal(true, err != nil)
+}
+
+func TestValidate(t *testing.T) {
+	dir := newTestRepository(t)
+	repository := "git::file://" + filepath.ToSlash(dir)
+
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:   "existing ref and submodule",
+			source: repository + "//vpc?ref=v1.0.0",
+		},
+		{
+			name:   "no submodule",
+			source: repository + "?ref=v1.0.0",
+		},
+		{
+			name:   "no revision",
+			source: repository + "//vpc/",
+		},
+		{
+			name:     "missing ref",
+			source:   repository + "//vpc?ref=v9.9.9",
+			expected: "ref v9.9.9 does not exist in " + module.Source{Module: filepath.ToSlash(dir)}.Repository(),
+		},
+		{
+			name:     "missing submodule",
+			source:   repository + "//dns?ref=v1.0.0",
+			expected: "submodule dns does not exist in " + module.Source{Module: filepath.ToSlash(dir)}.Repository() + " at v1.0.0",
+		},
+		{
+			name:     "file is not a submodule",
+			source:   repository + "//vpc/main.tf?ref=v1.0.0",
+			expected: "submodule vpc/main.tf does not exist in " + module.Source{Module: filepath.ToSlash(dir)}.Repository() + " at v1.0.0",
+		},
+		{
+			name:   "registry module is not validated",
+			source: "terraform-aws-modules/vpc/aws",
+		},
+	}
+
+	mirror := New("")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			source, err := module.ParseSource(tt.source)
+			assert.NoError(err)
+
+			// the second call is served from cache
+			for i := 0; i < 2; i++ {
+				err = mirror.Validate(source)
+				message := ""
+				if err != nil {
+					message = err.Error()
+				}
+				assert.Equal(tt.expected, message)
+			}
+		})
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	dir := newTestRepository(t)
+	testhelpers.CommitFiles(t, dir, "Add vpc variables", "vpc/variables.tf", "")
+	testhelpers.CommitFiles(t, dir, "Update readme", "README.md", "")
+	testhelpers.CommitFiles(t, dir, "Release v1.1.0", "CHANGELOG.md", "# Changelog\n\n## v1.1.0\n\n- Add variables\n\n## v1.0.0\n\n- Initial\n")
+	testhelpers.RunGit(t, dir, "tag", "v1.1.0")
+
+	parse := func(source string) module.Source {
+		s, err := module.ParseSource(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	repository := "git::file://" + filepath.ToSlash(dir)
+
+	tests := []struct {
+		name     string
+		before   string
+		after    string
+		expected updater.Changelog
+		isError  bool
+	}{
+		{
+			name:   "submodule",
+			before: repository + "//vpc?ref=v1.0.0",
+			after:  repository + "//vpc?ref=v1.1.0",
+			expected: updater.Changelog{
+				Commits: []string{"Add vpc variables"},
+				Notes:   []string{"## v1.1.0\n\n- Add variables"},
+			},
+		},
+		{
+			name:   "whole repository",
+			before: repository + "?ref=v1.0.0",
+			after:  repository + "?ref=v1.1.0",
+			expected: updater.Changelog{
+				Commits: []string{"Release v1.1.0", "Update readme", "Add vpc variables"},
+				Notes:   []string{"## v1.1.0\n\n- Add variables"},
+			},
+		},
+		{
+			name:     "different repositories",
+			before:   "git::https://github.com/org/modules.git?ref=v1.0.0",
+			after:    repository + "?ref=v1.1.0",
+			expected: updater.Changelog{},
+		},
+		{
+			name:    "missing ref",
+			before:  repository + "?ref=v0.9.0",
+			after:   repository + "?ref=v1.1.0",
+			isError: true,
+		},
+	}
+
+	mirror := New("")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			changelog, err := mirror.Changelog(parse(tt.before), parse(tt.after))
+			assert.Equal(tt.isError, err != nil)
+			assert.Equal(tt.expected, changelog)
+		})
+	}
+}
+
+func TestCheckInterface(t *testing.T) {
+	dir := newTestRepository(t)
+	testhelpers.CommitFiles(t, dir, "Add variables",
+		"vpc/variables.tf", "variable \"name\" {}\nvariable \"legacy\" {\n  default = false\n}\n",
+		"vpc/outputs.tf", "output \"id\" {\n  value = 1\n}\n",
+	)
+	testhelpers.RunGit(t, dir, "tag", "v1.1.0")
+	testhelpers.CommitFiles(t, dir, "Break interface",
+		"vpc/variables.tf", "variable \"name\" {}\nvariable \"region\" {}\n",
+		"vpc/outputs.tf", "",
+		"vpc/zone.tf.json", `{"variable": {"zone": {"type": "string"}}}`,
+		// files of other folders do not matter
+		"main.tf", "variable \"other\" {}\n",
+	)
+	testhelpers.RunGit(t, dir, "tag", "v2.0.0")
+
+	parse := func(source string) module.Source {
+		s, err := module.ParseSource(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	repository := "git::file://" + filepath.ToSlash(dir)
+	change := updater.Change{
+		Call:   updater.ModuleCall{Name: "vpc", Arguments: []string{"legacy", "name"}},
+		Before: parse(repository + "//vpc?ref=v1.1.0"),
+		After:  parse(repository + "//vpc?ref=v2.0.0"),
+	}
+
+	assert := testhelpers.Assert(t)
+	findings, err := New("").CheckInterface(change)
+	assert.NoError(err)
+	assert.Equal([]updater.InterfaceFinding{
+		{Kind: "required-variable", Name: "region", Breaking: true, Message: `required variable "region" is added, the call does not set it`},
+		{Kind: "required-variable", Name: "zone", Breaking: true, Message: `required variable "zone" is added, the call does not set it`},
+		{Kind: "removed-variable", Name: "legacy", Breaking: true, Message: `variable "legacy" is removed, the call sets it`},
+		{Kind: "removed-output", Name: "id", Message: `output "id" is removed`},
+	}, findings)
+
+	change.After = parse(repository + "//vpc?ref=v9.9.9")
+	_, err = New("").CheckInterface(change)
+	assert.Equal(true, err != nil)
+}
+
+func TestReleases(t *testing.T) {
+	dir := newTestRepository(t)
+	testhelpers.RunGit(t, dir, "tag", "v1.1.0")
+	assert := testhelpers.Assert(t)
+
+	source, err := module.ParseSource("git::file://" + filepath.ToSlash(dir) + "//vpc?ref=v1.0.0")
+	assert.NoError(err)
+	releases, err := New("").Releases(source)
+	assert.NoError(err)
+	assert.Equal(2, len(releases))
+	assert.Equal(module.Revision("v1.0.0"), releases[0].Revision)
+	assert.Equal(module.Revision("v1.1.0"), releases[1].Revision)
+	assert.Equal(false, releases[0].Date.IsZero())
+
+	_, err = New("").Releases(module.Source{SpecialPrefix: "git::", Scheme: "https", Host: "github.com", Module: "/org/modules.git"})
+	assert.Equal(true, err != nil)
+}
@@ -0,0 +1,97 @@
+package pathmatch
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// String returns the pattern the glob was compiled from
+func (g *Glob) String() string {
+	return g.pattern
+}
+
+// Match reports if slash-separated relative path matches the glob
+func (g *Glob) Match(name string) bool {
+	return matchSegments(g.segments, splitPath(name))
+}
+
+func matchSegments(pattern []string, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	// errors are not possible here, patterns are validated by CompileGlob
+	matched, _ := path.Match(pattern[0], name[0])
+
+	return matched && matchSegments(pattern[1:], name[1:])
+}
+
+// splitPath splits relative path into segments ignoring empty ones, so "./a//b/" gives "a" and "b"
+func splitPath(name string) []string {
+	segments := []string{}
+	for _, s := range strings.Split(name, "/") {
+		if s != "" && s != "." {
+			segments = append(segments, s)
+		}
+	}
+
+	return segments
+}
+
+// MatchAny reports if the path matches any of globs
+func MatchAny(globs []*Glob, name string) bool {
+	for _, g := range globs {
+		if g.Match(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CompileGlob validates the pattern and prepares it for matching
+func CompileGlob(pattern string) (*Glob, error) {
+	segments := splitPath(pattern)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid pattern %q: pattern is empty", pattern)
+	}
+
+	for _, s := range segments {
+		if s == "**" {
+			continue
+		}
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %s", pattern, err)
+		}
+	}
+
+	return &Glob{pattern: pattern, segments: segments}, nil
+}
+
+// CompileGlobs compiles all patterns, see CompileGlob
+func CompileGlobs(patterns []string) ([]*Glob, error) {
+	globs := make([]*Glob, 0, len(patterns))
+	for _, p := range patterns {
+		g, err := CompileGlob(p)
+		if err != nil {
+			return nil, err
+		}
+		globs = append(globs, g)
+	}
+
+	return globs, nil
+}
@@ -0,0 +1,56 @@
+package pathmatch
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestGlobMatch(t *testing.T) {
+	testCases := []struct {
+		pattern        string
+		name           string
+		expectedResult bool
+	}{
+		{pattern: "**/examples/**", name: "examples/main.tf", expectedResult: true},
+		{pattern: "**/examples/**", name: "modules/vpc/examples/complete/main.tf", expectedResult: true},
+		{pattern: "**/examples/**", name: "examples", expectedResult: true},
+		{pattern: "**/examples/**", name: "modules/examples.tf", expectedResult: false},
+		{pattern: "envs/prod/**", name: "envs/prod/eu/main.tf", expectedResult: true},
+		{pattern: "envs/prod/**", name: "envs/stage/main.tf", expectedResult: false},
+		{pattern: "envs/*/main.tf", name: "envs/prod/main.tf", expectedResult: true},
+		{pattern: "envs/*/main.tf", name: "envs/prod/eu/main.tf", expectedResult: false},
+		{pattern: "*.tf", name: "main.tf", expectedResult: true},
+		{pattern: "*.tf", name: "envs/main.tf", expectedResult: false},
+		{pattern: "./envs//prod/", name: "envs/prod", expectedResult: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			glob, err := CompileGlob(tc.pattern)
+			assert.NoError(err)
+			assert.Equal(tc.expectedResult, glob.Match(tc.name))
+		})
+	}
+}
+
+func TestCompileGlobs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		patterns      []string
+		expectedError bool
+	}{
+		{name: "valid", patterns: []string{"**/examples/**", "envs/[a-z]*/**"}},
+		{name: "bad character class", patterns: []string{"envs/[a-z/**"}, expectedError: true},
+		{name: "empty", patterns: []string{"/"}, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			_, err := CompileGlobs(tc.patterns)
+			assert.Equal(tc.expectedError, err != nil)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package pathmatch
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// match reports if the rules of the list decide about the path and if the path is ignored,
+// the last matching rule wins as in git
+func (l *IgnoreList) match(name string, isDir bool) (ignored bool, matched bool) {
+	rel := name
+	if l.dir != "" {
+		if !strings.HasPrefix(name, l.dir+"/") {
+			return false, false
+		}
+		rel = strings.TrimPrefix(name, l.dir+"/")
+	}
+
+	for _, r := range l.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.glob.Match(rel) {
+			ignored, matched = !r.negate, true
+		}
+	}
+
+	return ignored, matched
+}
+
+// Ignored reports if the path is ignored by the lists ordered from the root to the deepest directory,
+// rules of deeper lists take precedence
+//
+// name is slash-separated path relative to the traversal root
+func Ignored(lists []*IgnoreList, name string, isDir bool) bool {
+	for i := len(lists) - 1; i >= 0; i-- {
+		if ignored, matched := lists[i].match(name, isDir); matched {
+			return ignored
+		}
+	}
+
+	return false
+}
+
+// ParseIgnore parses content of ignore file in .gitignore format located in dir,
+// dir is slash-separated and relative to the traversal root
+//
+// Patterns without slash match at any depth, patterns with slash are anchored to dir,
+// trailing slash limits the pattern to directories and leading "!" re-includes matching paths
+func ParseIgnore(dir string, content []byte) (*IgnoreList, error) {
+	list := &IgnoreList{dir: strings.Join(splitPath(dir), "/")}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+
+		if !strings.Contains(line, "/") {
+			line = "**/" + line
+		}
+
+		glob, err := CompileGlob(strings.TrimPrefix(line, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+		rule.glob = glob
+		list.rules = append(list.rules, rule)
+	}
+
+	return list, scanner.Err()
+}
@@ -0,0 +1,59 @@
+package pathmatch
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestIgnored(t *testing.T) {
+	root, err := ParseIgnore("", []byte(`
+# generated stacks
+generated/
+*.bak.tf
+/vendor
+!keep.bak.tf
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested, err := ParseIgnore("envs", []byte(`
+prod/legacy.tf
+!generated/
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lists := []*IgnoreList{root, nested}
+
+	testCases := []struct {
+		name           string
+		isDir          bool
+		expectedResult bool
+	}{
+		{name: "generated", isDir: true, expectedResult: true},
+		{name: "modules/generated", isDir: true, expectedResult: true},
+		{name: "generated", isDir: false, expectedResult: false},
+		{name: "modules/main.bak.tf", expectedResult: true},
+		{name: "modules/keep.bak.tf", expectedResult: false},
+		{name: "vendor", isDir: true, expectedResult: true},
+		{name: "modules/vendor", isDir: true, expectedResult: false},
+		{name: "envs/prod/legacy.tf", expectedResult: true},
+		{name: "prod/legacy.tf", expectedResult: false},
+		{name: "envs/generated", isDir: true, expectedResult: false},
+		{name: "envs/main.tf", expectedResult: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			assert.Equal(tc.expectedResult, Ignored(lists, tc.name, tc.isDir))
+		})
+	}
+}
+
+func TestParseIgnoreInvalid(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	_, err := ParseIgnore("", []byte("ok.tf\n[broken\n"))
+	assert.Equal("line 2: invalid pattern \"**/[broken\": syntax error in pattern", err.Error())
+}
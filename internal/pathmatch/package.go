@@ -0,0 +1,21 @@
+package pathmatch
+
+// Glob is a slash-separated glob pattern, "**" segment matches any number of path segments
+// and other segments follow path.Match syntax
+type Glob struct {
+	pattern  string
+	segments []string
+}
+
+// IgnoreList holds rules of a single ignore file in .gitignore format
+type IgnoreList struct {
+	// dir is the directory of ignore file relative to the traversal root, empty for the root itself
+	dir   string
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	glob    *Glob
+	negate  bool
+	dirOnly bool
+}
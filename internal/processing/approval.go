@@ -0,0 +1,90 @@
+package processing
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Approver decides if the change proposed by strategy should be applied,
+// the change already holds its explanation, changelog and interface findings when they are collected
+//
+// Approve is never called concurrently, manager processes files one by one when approver is set
+type Approver interface {
+	Approve(Change) bool
+}
+
+// InteractiveApprover asks for approval of each change and reads answers line by line,
+// so answers might come from a terminal as well as from a script
+type InteractiveApprover struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// acceptedFile holds the file which all changes are accepted in
+	acceptedFile string
+	quit         bool
+}
+
+var _ Approver = (*InteractiveApprover)(nil)
+
+// Approve shows the change with its explanation, changelog and interface changes, if known,
+// and asks to accept it, skip it, accept all changes in the file or quit
+//
+// After quit or end of input all remaining changes are rejected
+func (a *InteractiveApprover) Approve(c Change) bool {
+	if a.quit {
+		return false
+	}
+
+	if a.acceptedFile != "" && a.acceptedFile == c.Call.File {
+		return true
+	}
+
+	fmt.Fprintf(a.out, "In file %s, %s %q:\n  - %s\n  + %s\n", c.Call.File, c.Call.Block, c.Call.Name, c.Before.String(), c.After.String())
+	for _, reason := range c.Explanation {
+		fmt.Fprintln(a.out, "    "+reason)
+	}
+	for _, subject := range c.Changelog.Commits {
+		fmt.Fprintln(a.out, "    * "+subject)
+	}
+	for _, f := range c.Interface {
+		if f.Breaking {
+			fmt.Fprintln(a.out, "    breaking: "+f.Message)
+		} else {
+			fmt.Fprintln(a.out, "    interface: "+f.Message)
+		}
+	}
+	for {
+		fmt.Fprint(a.out, "Apply this change? [y]es, [n]o, [a]ll in this file, [q]uit: ")
+
+		answer, err := a.in.ReadString('\n')
+		if err != nil && answer == "" {
+			fmt.Fprintln(a.out)
+			a.quit = true
+			return false
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		case "a", "all":
+			a.acceptedFile = c.Call.File
+			return true
+		case "q", "quit":
+			a.quit = true
+			return false
+		}
+
+		if err != nil {
+			a.quit = true
+			return false
+		}
+	}
+}
+
+func NewInteractiveApprover(in io.Reader, out io.Writer) *InteractiveApprover {
+	return &InteractiveApprover{in: bufio.NewReader(in), out: out}
+}
@@ -0,0 +1,99 @@
+package processing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+func TestInteractiveApprover(t *testing.T) {
+	change := func(file string) Change {
+		return Change{
+			Call:   ModuleCall{File: file, Name: "vpc", Block: "module"},
+			Before: module.Source{Module: "vpc", Revision: "v1"},
+			After:  module.Source{Module: "vpc", Revision: "v2"},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		input          string
+		files          []string
+		expectedResult []bool
+	}{
+		{
+			name:           "yes and no",
+			input:          "y\nn\n",
+			files:          []string{"/a.tf", "/a.tf"},
+			expectedResult: []bool{true, false},
+		},
+		{
+			name:           "all in file",
+			input:          "a\nn\n",
+			files:          []string{"/a.tf", "/a.tf", "/b.tf"},
+			expectedResult: []bool{true, true, false},
+		},
+		{
+			name:           "quit rejects the rest",
+			input:          "q\ny\n",
+			files:          []string{"/a.tf", "/b.tf"},
+			expectedResult: []bool{false, false},
+		},
+		{
+			name:           "unknown answer is asked again",
+			input:          "maybe\nyes\n",
+			files:          []string{"/a.tf"},
+			expectedResult: []bool{true},
+		},
+		{
+			name:           "end of input rejects",
+			input:          "y",
+			files:          []string{"/a.tf", "/a.tf"},
+			expectedResult: []bool{true, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			approver := NewInteractiveApprover(strings.NewReader(tc.input), &bytes.Buffer{})
+
+			result := []bool{}
+			for _, f := range tc.files {
+				result = append(result, approver.Approve(change(f)))
+			}
+			assert.Equal(tc.expectedResult, result)
+		})
+	}
+}
+
+func TestInteractiveApproverPrompt(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	out := &bytes.Buffer{}
+	approver := NewInteractiveApprover(strings.NewReader("n\n"), out)
+
+	approver.Approve(Change{
+		Call:        ModuleCall{File: "/stack/main.tf", Name: "vpc", Block: "module"},
+		Before:      module.Source{Module: "vpc", Revision: "v1"},
+		After:       module.Source{Module: "vpc", Revision: "v2"},
+		Explanation: []string{"v3 is held back"},
+		Changelog:   Changelog{Commits: []string{"Remove legacy variable", "Add zone output"}},
+		Interface: []InterfaceFinding{
+			{Kind: "removed-variable", Name: "legacy", Breaking: true, Message: "variable legacy is removed"},
+			{Kind: "added-output", Name: "zone", Message: "output zone is added"},
+		},
+	})
+
+	assert.Equal(`In file /stack/main.tf, module "vpc":
+  - vpc?ref=v1
+  + vpc?ref=v2
+    v3 is held back
+    * Remove legacy variable
+    * Add zone output
+    breaking: variable legacy is removed
+    interface: output zone is added
+Apply this change? [y]es, [n]o, [a]ll in this file, [q]uit: `, out.String())
+}
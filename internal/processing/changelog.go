@@ -0,0 +1,24 @@
+package processing
+
+import "github.com/maxim-nazarenko/tf-module-update/module"
+
+// Changelog describes what changed in the module between the old and the new revision
+type Changelog struct {
+	// Commits holds subjects of commits touching the module, the newest first
+	Commits []string
+
+	// Notes holds changelog file sections of revisions after the old one up to the new one
+	Notes []string
+}
+
+// IsEmpty reports if nothing is known about the changes
+func (c Changelog) IsEmpty() bool {
+	return len(c.Commits) == 0 && len(c.Notes) == 0
+}
+
+// ChangelogProvider collects changes made in the module between revisions of the sources
+//
+// Changelog might be called concurrently when files are processed by several jobs
+type ChangelogProvider interface {
+	Changelog(before, after module.Source) (Changelog, error)
+}
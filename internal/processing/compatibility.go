@@ -0,0 +1,24 @@
+package processing
+
+// InterfaceFinding is a difference of module interface between the old and the new revision
+type InterfaceFinding struct {
+	// Kind is the kind of difference, e.g. "removed-variable"
+	Kind string
+
+	// Name is the name of the variable or output
+	Name string
+
+	// Breaking reports that the call stops working with the new revision, e.g. it sets a removed variable
+	Breaking bool
+
+	// Message describes the difference for humans
+	Message string
+}
+
+// InterfaceChecker compares variables and outputs of the module at revisions of the change
+// and checks the differences against arguments of the call
+//
+// CheckInterface might be called concurrently when files are processed by several jobs
+type InterfaceChecker interface {
+	CheckInterface(c Change) ([]InterfaceFinding, error)
+}
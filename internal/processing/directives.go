@@ -0,0 +1,123 @@
+package processing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+// directivePrefix marks comments with instructions for the tool, e.g.
+//
+// # tf-module-update:ignore
+// # tf-module-update: constraint="~> 2.1"
+const directivePrefix = "tf-module-update:"
+
+var directiveArgument = regexp.MustCompile(`^(\w+)\s*=\s*"([^"]*)"\s*`)
+
+// directive is an instruction read from a comment next to module block
+type directive struct {
+	// text is the directive as written in the comment, used as a reason in reports
+	text       string
+	ignore     bool
+	constraint *module.Constraint
+}
+
+// blockDirectives reads directives from comments directly above the block and around its source attribute
+func blockDirectives(block *hclwrite.Block) ([]directive, error) {
+	comments := []string{}
+
+	// lead comments go first in block tokens
+	for _, t := range block.BuildTokens(nil) {
+		if t.Type != hclsyntax.TokenComment {
+			break
+		}
+		comments = append(comments, string(t.Bytes))
+	}
+
+	if sourceAttr := block.Body().GetAttribute("source"); sourceAttr != nil {
+		for _, t := range sourceAttr.BuildTokens(nil) {
+			if t.Type == hclsyntax.TokenComment {
+				comments = append(comments, string(t.Bytes))
+			}
+		}
+	}
+
+	directives := []directive{}
+	for _, c := range comments {
+		d, ok, err := parseDirective(c)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			directives = append(directives, d)
+		}
+	}
+
+	return directives, nil
+}
+
+// parseDirective parses a single comment, the second value is false if the comment is not a directive
+func parseDirective(comment string) (directive, bool, error) {
+	text := strings.TrimSpace(comment)
+	for _, marker := range []string{"#", "//", "/*"} {
+		if strings.HasPrefix(text, marker) {
+			text = strings.TrimSpace(strings.TrimPrefix(text, marker))
+			break
+		}
+	}
+	text = strings.TrimSpace(strings.TrimSuffix(text, "*/"))
+
+	if !strings.HasPrefix(text, directivePrefix) {
+		return directive{}, false, nil
+	}
+
+	d := directive{text: text}
+	rest := strings.TrimSpace(strings.TrimPrefix(text, directivePrefix))
+	if rest == "ignore" || strings.HasPrefix(rest, "ignore ") {
+		d.ignore = true
+		return d, true, nil
+	}
+
+	if rest == "" {
+		return d, false, fmt.Errorf("empty directive: %s", text)
+	}
+
+	for rest != "" {
+		match := directiveArgument.FindStringSubmatch(rest)
+		if match == nil {
+			return d, false, fmt.Errorf("cannot parse directive: %s", text)
+		}
+		rest = rest[len(match[0]):]
+
+		switch match[1] {
+		case "constraint":
+			constraint, err := module.ParseConstraint(match[2])
+			if err != nil {
+				return d, false, fmt.Errorf("invalid directive %s: %s", text, err)
+			}
+			d.constraint = &constraint
+		default:
+			return d, false, fmt.Errorf("unknown directive argument %q: %s", match[1], text)
+		}
+	}
+
+	return d, true, nil
+}
+
+// skipReason returns why the change must not be applied according to directives, empty string allows the change
+func skipReason(directives []directive, newSource module.Source) string {
+	for _, d := range directives {
+		if d.ignore {
+			return d.text
+		}
+		if d.constraint != nil && !d.constraint.Allows(newSource.Revision) {
+			return fmt.Sprintf("%s does not allow revision %s", d.text, newSource.Revision)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,52 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+func TestParseDirective(t *testing.T) {
+	testCases := []struct {
+		name               string
+		comment            string
+		expectedOk         bool
+		expectedError      bool
+		expectedIgnore     bool
+		expectedConstraint string
+	}{
+		{name: "regular comment", comment: "# just a comment\n"},
+		{name: "ignore", comment: "# tf-module-update:ignore\n", expectedOk: true, expectedIgnore: true},
+		{name: "ignore with explanation", comment: "// tf-module-update:ignore broken in v2\n", expectedOk: true, expectedIgnore: true},
+		{name: "constraint", comment: `# tf-module-update: constraint="~> 2.1"`, expectedOk: true, expectedConstraint: "~> 2.1"},
+		{name: "block comment", comment: `/* tf-module-update: constraint=">= 1.0, < 2.0" */`, expectedOk: true, expectedConstraint: ">= 1.0, < 2.0"},
+		{name: "empty directive", comment: "# tf-module-update:", expectedError: true},
+		{name: "unknown argument", comment: `# tf-module-update: pin="v1.0.0"`, expectedError: true},
+		{name: "invalid constraint", comment: `# tf-module-update: constraint="~> main"`, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			d, ok, err := parseDirective(tc.comment)
+			assert.Equal(tc.expectedError, err != nil)
+			assert.Equal(tc.expectedOk, ok)
+			assert.Equal(tc.expectedIgnore, d.ignore)
+			if tc.expectedConstraint != "" {
+				assert.Equal(tc.expectedConstraint, d.constraint.String())
+			}
+		})
+	}
+}
+
+func TestSkipReason(t *testing.T) {
+	constraint, _ := module.ParseConstraint("~> 2.1")
+	directives := []directive{{text: `tf-module-update: constraint="~> 2.1"`, constraint: &constraint}}
+	assert := testhelpers.Assert(t)
+
+	assert.Equal("", skipReason(directives, module.Source{Revision: "v2.5.0"}))
+	assert.Equal(`tf-module-update: constraint="~> 2.1" does not allow revision v3.0.0`, skipReason(directives, module.Source{Revision: "v3.0.0"}))
+	assert.Equal("tf-module-update:ignore", skipReason([]directive{{text: "tf-module-update:ignore", ignore: true}}, module.Source{}))
+}
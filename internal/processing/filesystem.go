@@ -0,0 +1,92 @@
+package processing
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/fileutil"
+)
+
+// FileWriter writes updated files back, names are the same as used for reading
+type FileWriter interface {
+	WriteFile(name string, data []byte) error
+}
+
+// BackupWriter is implemented by writers which write backups differently from other files,
+// backups are written with WriteFile of writers which do not implement it
+type BackupWriter interface {
+	// WriteBackup writes copy of the original file with the given name
+	WriteBackup(name string, original string, data []byte) error
+}
+
+// osFS reads the local file system
+//
+// Unlike os.DirFS, it accepts absolute and relative paths as they are given by user
+type osFS struct{}
+
+var (
+	_ fs.StatFS     = osFS{}
+	_ fs.ReadDirFS  = osFS{}
+	_ fs.ReadFileFS = osFS{}
+)
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// osWriter writes files to the local file system
+type osWriter struct{}
+
+var _ BackupWriter = osWriter{}
+
+// WriteFile replaces the file atomically keeping its mode and owner
+func (osWriter) WriteFile(name string, data []byte) error {
+	return fileutil.WriteFileAtomic(name, data)
+}
+
+// WriteBackup writes the backup atomically, it gets mode and owner of the original file as they hold the same content
+func (osWriter) WriteBackup(name string, original string, data []byte) error {
+	return fileutil.WriteFileAtomicAs(name, data, original)
+}
+
+// noWriter refuses to write files, it is used for file systems given without a writer,
+// so files of in-memory or archive file systems never end up on the local disk
+type noWriter struct{}
+
+func (noWriter) WriteFile(name string, _ []byte) error {
+	return fmt.Errorf("%s: cannot write file, no writer is given for the file system", name)
+}
+
+// normalizePath turns the path given by user into the one used in results and for reading,
+// local paths are made absolute while paths of other file systems are only cleaned
+func (m *RevisionManager) normalizePath(p string) (string, error) {
+	if _, ok := m.fsys.(osFS); ok {
+		return filepath.Abs(p)
+	}
+
+	return path.Clean(filepath.ToSlash(p)), nil
+}
+
+// joinPath joins elements of path, paths of file systems other than the local one are always slash-separated
+func (m *RevisionManager) joinPath(elem ...string) string {
+	if _, ok := m.fsys.(osFS); ok {
+		return filepath.Join(elem...)
+	}
+
+	return path.Join(elem...)
+}
@@ -0,0 +1,297 @@
+package processing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/internal/processing/logging"
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+)
+
+// mapWriter records written files in memory
+type mapWriter map[string]string
+
+func (w mapWriter) WriteFile(name string, data []byte) error {
+	w[name] = string(data)
+	return nil
+}
+
+func TestProcessPathsFS(t *testing.T) {
+	vpcV1 := `module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`
+	vpcV2 := `module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0"
+}
+`
+	fsys := fstest.MapFS{
+		"stack/main.tf":             {Data: []byte(vpcV1)},
+		"stack/outputs.tf":          {Data: []byte(`output "id" { value = 1 }`)},
+		"stack/README.md":           {Data: []byte(vpcV1)},
+		"stack/.terraform/mod.tf":   {Data: []byte(vpcV1)},
+		"other/nested/main.tf.json": {Data: []byte(`{"module": {"vpc": {"source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"}}}`)},
+	}
+
+	testCases := []struct {
+		name            string
+		paths           []string
+		write           bool
+		withoutWriter   bool
+		expectedWritten mapWriter
+		expectedFiles   []string
+		expectedErrors  bool
+	}{
+		{
+			name:            "whole tree",
+			paths:           []string{"."},
+			write:           true,
+			expectedWritten: mapWriter{"stack/main.tf": vpcV2, "other/nested/main.tf.json": `{"module": {"vpc": {"source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0"}}}`},
+			expectedFiles:   []string{"other/nested/main.tf.json", "stack/main.tf"},
+		},
+		{
+			name:            "single file without writing",
+			paths:           []string{"./stack/main.tf"},
+			expectedWritten: mapWriter{},
+			expectedFiles:   []string{"stack/main.tf"},
+		},
+		{
+			name:            "file system without writer",
+			paths:           []string{"stack"},
+			write:           true,
+			withoutWriter:   true,
+			expectedWritten: mapWriter{},
+			expectedFiles:   []string{"stack/main.tf"},
+			expectedErrors:  true,
+		},
+		{
+			name:            "missing path",
+			paths:           []string{"missing"},
+			write:           true,
+			expectedWritten: mapWriter{},
+			expectedFiles:   []string{},
+			expectedErrors:  true,
+		},
+	}
+
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v1.1.0")})
+	}).WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0")))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			written := mapWriter{}
+			config := Config{
+				Write:            tc.write,
+				ExcludeItemsFunc: DefaultExclusionFunc,
+				FS:               fsys,
+				Writer:           written,
+			}
+			if tc.withoutWriter {
+				config.Writer = nil
+			}
+			manager := NewManager(config, strategy)
+
+			results := NewResults(logging.INFO)
+			manager.ProcessPaths(tc.paths, results)
+
+			files := []string{}
+			for _, c := range results.ModuleCalls() {
+				files = append(files, c.File)
+			}
+			assert.Equal(tc.expectedWritten, written)
+			assert.Equal(tc.expectedFiles, files)
+			assert.Equal(tc.expectedErrors, results.HasErrors())
+		})
+	}
+}
+
+func TestCollectFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":                          {Data: []byte("generated/\noverride.tf\n*_override.tf\n*_override.tf.json\n")},
+		"main.tf":                             {},
+		"override.tf":                         {},
+		"envs/prod/vpc_override.tf.json":      {},
+		"envs/prod/main.tf":                   {},
+		"envs/prod/eu/main.tf":                {},
+		"envs/stage/main.tf":                  {},
+		"envs/stage/.tf-module-update-ignore": {Data: []byte("legacy.tf\n")},
+		"envs/stage/legacy.tf":                {},
+		"modules/vpc/examples/main.tf":        {},
+		"generated/main.tf":                   {},
+	}
+
+	testCases := []struct {
+		name           string
+		config         Config
+		paths          []string
+		expectedResult []string
+		expectedErrors bool
+	}{
+		{
+			name:   "ignore files",
+			config: Config{IgnoreFiles: DefaultIgnoreFiles},
+			paths:  []string{"."},
+			// override files are processed even though .gitignore lists them
+			expectedResult: []string{
+				"envs/prod/eu/main.tf", "envs/prod/main.tf", "envs/prod/vpc_override.tf.json", "envs/stage/main.tf", "main.tf",
+				"modules/vpc/examples/main.tf", "override.tf",
+			},
+		},
+		{
+			name:   "no ignore files",
+			config: Config{},
+			paths:  []string{"envs"},
+			expectedResult: []string{
+				"envs/prod/eu/main.tf", "envs/prod/main.tf", "envs/prod/vpc_override.tf.json", "envs/stage/legacy.tf", "envs/stage/main.tf",
+			},
+		},
+		{
+			name:           "include and exclude",
+			config:         Config{IgnoreFiles: DefaultIgnoreFiles, Include: []string{"envs/**", "modules/**"}, Exclude: []string{"**/examples/**", "envs/prod/eu", "**/*_override.tf.json"}},
+			paths:          []string{"."},
+			expectedResult: []string{"envs/prod/main.tf", "envs/stage/main.tf"},
+		},
+		{
+			name:           "patterns are relative to walked path",
+			config:         Config{Include: []string{"prod/*.tf"}},
+			paths:          []string{"envs"},
+			expectedResult: []string{"envs/prod/main.tf"},
+		},
+		{
+			name:           "max depth",
+			config:         Config{IgnoreFiles: DefaultIgnoreFiles, MaxDepth: 2},
+			paths:          []string{"envs"},
+			expectedResult: []string{"envs/prod/main.tf", "envs/prod/vpc_override.tf.json", "envs/stage/main.tf"},
+		},
+		{
+			name:           "explicit files bypass patterns",
+			config:         Config{IgnoreFiles: DefaultIgnoreFiles, Exclude: []string{"**"}},
+			paths:          []string{"generated/main.tf"},
+			expectedResult: []string{"generated/main.tf"},
+		},
+		{
+			name:           "invalid pattern",
+			config:         Config{Exclude: []string{"[broken"}},
+			paths:          []string{"."},
+			expectedResult: []string(nil),
+			expectedErrors: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			tc.config.FS = fsys
+			tc.config.ExcludeItemsFunc = DefaultExclusionFunc
+			manager := NewManager(tc.config, nil)
+
+			results := &Results{}
+			assert.Equal(tc.expectedResult, manager.collectFiles(tc.paths, results))
+			assert.Equal(tc.expectedErrors, results.HasErrors())
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	testCases := []struct {
+		name            string
+		current         string
+		backup          bool
+		expectedWritten mapWriter
+		expectedResult  Written
+		expectedError   bool
+	}{
+		{
+			name:            "without backup",
+			current:         "old",
+			expectedWritten: mapWriter{"main.tf": "new"},
+			expectedResult:  Written{File: "main.tf", SHA256: contentHash([]byte("new"))},
+		},
+		{
+			name:            "with backup",
+			current:         "old",
+			backup:          true,
+			expectedWritten: mapWriter{"main.tf": "new", "main.tf.bak": "old"},
+			expectedResult:  Written{File: "main.tf", Backup: "main.tf.bak", SHA256: contentHash([]byte("new"))},
+		},
+		{
+			name:            "changed since read",
+			current:         "edited",
+			backup:          true,
+			expectedWritten: mapWriter{},
+			expectedError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			written := mapWriter{}
+			manager := NewManager(Config{
+				Write:  true,
+				Backup: tc.backup,
+				FS:     fstest.MapFS{"main.tf": {Data: []byte(tc.current)}},
+				Writer: written,
+			}, nil)
+
+			result, err := manager.writeFile("main.tf", []byte("old"), []byte("new"))
+			assert.Equal(tc.expectedError, err != nil)
+			assert.Equal(tc.expectedResult, result)
+			assert.Equal(tc.expectedWritten, written)
+		})
+	}
+}
+
+// backupMapWriter records backups separately from other written files
+type backupMapWriter struct {
+	mapWriter
+	backups map[string]string
+}
+
+func (w backupMapWriter) WriteBackup(name string, original string, data []byte) error {
+	w.backups[name] = original + ":" + string(data)
+	return nil
+}
+
+func TestWriteBackup(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	written := backupMapWriter{mapWriter: mapWriter{}, backups: map[string]string{}}
+	manager := NewManager(Config{
+		Write:  true,
+		Backup: true,
+		FS:     fstest.MapFS{"main.tf": {Data: []byte("old")}},
+		Writer: written,
+	}, nil)
+
+	_, err := manager.writeFile("main.tf", []byte("old"), []byte("new"))
+	assert.NoError(err)
+	assert.Equal(mapWriter{"main.tf": "new"}, written.mapWriter)
+	assert.Equal(map[string]string{"main.tf.bak": "main.tf:old"}, written.backups)
+}
+
+func TestOSWriter(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	dir := t.TempDir()
+	original := filepath.Join(dir, "main.tf")
+	assert.NoError(os.WriteFile(original, []byte("old"), 0600))
+
+	// files with backup suffix are written as any other file
+	name := filepath.Join(dir, "legacy.tf.bak")
+	assert.NoError(osWriter{}.WriteFile(name, []byte("data")))
+	content, err := os.ReadFile(name)
+	assert.NoError(err)
+	assert.Equal("data", string(content))
+
+	backup := original + BackupSuffix
+	assert.NoError(osWriter{}.WriteBackup(backup, original, []byte("old")))
+	info, err := os.Stat(backup)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+}
@@ -0,0 +1,272 @@
+package processing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// jsonSource points to a module source string value inside of JSON document
+//
+// start and end are byte offsets of the value, including quotes
+type jsonSource struct {
+	name    string
+	start   int
+	end     int
+	value   string
+	version string
+
+	// keys holds names of all attributes of the module block
+	keys []string
+}
+
+func isJSONFile(path string) bool {
+	return strings.HasSuffix(path, ".tf.json") || strings.HasSuffix(path, ".tofu.json")
+}
+
+// updateJSONFileBody updates module sources in Terraform JSON syntax file
+//
+// Only string values are replaced in place, so the key order, indentation
+// and everything else in the original file stays untouched
+func (m *RevisionManager) updateJSONFileBody(src []byte, normalizedPath string, results *Results) ([]byte, error) {
+	sources, err := jsonModuleSources(src)
+	if err != nil {
+		return src, fmt.Errorf("parsing JSON syntax failed: %s: %s", normalizedPath, err)
+	}
+
+	strategy := m.strategyFor(normalizedPath)
+	updated := make([]byte, 0, len(src))
+	offset := 0
+	for _, s := range sources {
+		call := newModuleCall(normalizedPath, s.name, []string{"module"}, s.value, s.version, s.keys)
+		newSource, sourceResults := m.updateSource(call, strategy, nil)
+		results.Append(sourceResults)
+
+		call.Source = newSource
+		results.Append(call)
+		if newSource == s.value {
+			continue
+		}
+
+		encoded, err := encodeJSONString(newSource)
+		if err != nil {
+			return src, err
+		}
+
+		updated = append(updated, src[offset:s.start]...)
+		updated = append(updated, encoded...)
+		offset = s.end
+	}
+	updated = append(updated, src[offset:]...)
+
+	return updated, nil
+}
+
+// jsonModuleSources finds all "module.<name>.source" string values in Terraform JSON document
+//
+// Both forms of blocks are supported: objects and arrays of objects
+func jsonModuleSources(src []byte) ([]jsonSource, error) {
+	w := jsonWalker{src: src, decoder: json.NewDecoder(bytes.NewReader(src))}
+
+	if err := w.expectDelim('{'); err != nil {
+		return nil, err
+	}
+
+	err := w.objectKeys(func(key string) error {
+		if key != "module" {
+			return w.skipValue()
+		}
+
+		return w.objectsOrArray(func() error {
+			return w.objectKeys(func(name string) error {
+				return w.moduleBody(name)
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return w.sources, nil
+}
+
+type jsonWalker struct {
+	src     []byte
+	decoder *json.Decoder
+	sources []jsonSource
+}
+
+// moduleBody collects "source" and "version" attributes and names of all attributes of a single module block
+func (w *jsonWalker) moduleBody(name string) error {
+	return w.objectsOrArray(func() error {
+		sourceIndex := -1
+		version := ""
+		keys := []string{}
+		err := w.objectKeys(func(key string) error {
+			// "//" key holds comments in Terraform JSON syntax
+			if key != "//" {
+				keys = append(keys, key)
+			}
+			if key == "version" {
+				token, err := w.decoder.Token()
+				if err != nil {
+					return err
+				}
+				if delim, ok := token.(json.Delim); ok {
+					return w.skipDelimited(delim)
+				}
+				version = fmt.Sprint(token)
+				return nil
+			}
+
+			if key != "source" {
+				return w.skipValue()
+			}
+
+			offset := int(w.decoder.InputOffset())
+			token, err := w.decoder.Token()
+			if err != nil {
+				return err
+			}
+
+			switch t := token.(type) {
+			case string:
+				end := int(w.decoder.InputOffset())
+				sourceIndex = len(w.sources)
+				w.sources = append(w.sources, jsonSource{
+					name:  name,
+					start: offset + bytes.IndexByte(w.src[offset:end], '"'),
+					end:   end,
+					value: t,
+				})
+			case json.Delim:
+				return w.skipDelimited(t)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if sourceIndex >= 0 {
+			w.sources[sourceIndex].version = version
+			w.sources[sourceIndex].keys = keys
+		}
+
+		return nil
+	})
+}
+
+// objectsOrArray calls f for a single object or for each object in array,
+// the opening delimiter of the object is already consumed when f is called
+func (w *jsonWalker) objectsOrArray(f func() error) error {
+	token, err := w.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		return f()
+	case json.Delim('['):
+		for w.decoder.More() {
+			if err := w.expectDelim('{'); err != nil {
+				return err
+			}
+			if err := f(); err != nil {
+				return err
+			}
+		}
+		return w.expectDelim(']')
+	}
+
+	return fmt.Errorf("expected object or array but got %v", token)
+}
+
+// objectKeys calls f for each key of an object, f must consume the value
+// the opening delimiter must be already consumed, the closing one is consumed by objectKeys
+func (w *jsonWalker) objectKeys(f func(key string) error) error {
+	for w.decoder.More() {
+		token, err := w.decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("expected object key but got %v", token)
+		}
+		if err := f(key); err != nil {
+			return err
+		}
+	}
+
+	return w.expectDelim('}')
+}
+
+func (w *jsonWalker) skipValue() error {
+	token, err := w.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); ok {
+		return w.skipDelimited(delim)
+	}
+
+	return nil
+}
+
+// skipDelimited skips the rest of object or array which opening delimiter is already consumed
+func (w *jsonWalker) skipDelimited(opening json.Delim) error {
+	if opening != '{' && opening != '[' {
+		return fmt.Errorf("unexpected delimiter %v", opening)
+	}
+
+	depth := 1
+	for depth > 0 {
+		token, err := w.decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+
+	return nil
+}
+
+func (w *jsonWalker) expectDelim(delim json.Delim) error {
+	token, err := w.decoder.Token()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("unexpected end of JSON, expected %v", delim)
+	}
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %v but got %v", delim, token)
+	}
+
+	return nil
+}
+
+// encodeJSONString encodes string as JSON value without escaping of HTML characters,
+// so query strings like "?ref=v1&depth=1" stay readable
+func encodeJSONString(s string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
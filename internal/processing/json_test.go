@@ -0,0 +1,82 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+)
+
+func TestUpdateJSONFileBody(t *testing.T) {
+	testCases := []struct {
+		name           string
+		src            string
+		expectedResult string
+		expectedError  bool
+	}{
+		{
+			name: "object form keeps order and indentation",
+			src: `{
+    "module": {
+        "vpc": {
+            "source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0",
+            "cidr":   "10.0.0.0/16"
+        },
+        "db": {"source": "git::https://github.com/example-org/modules.git//db?ref=v2.0.0"}
+    },
+    "locals": {"source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"}
+}`,
+			expectedResult: `{
+    "module": {
+        "vpc": {
+            "source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0",
+            "cidr":   "10.0.0.0/16"
+        },
+        "db": {"source": "git::https://github.com/example-org/modules.git//db?ref=v2.0.0"}
+    },
+    "locals": {"source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"}
+}`,
+		},
+		{
+			name: "array form",
+			src: `{"module": [
+  {"vpc": [{"source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0", "tags": {"a": ["b"]}}]},
+  {"vpc2": {"tags": {"source": "x"}, "source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"}}
+]}`,
+			expectedResult: `{"module": [
+  {"vpc": [{"source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0", "tags": {"a": ["b"]}}]},
+  {"vpc2": {"tags": {"source": "x"}, "source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0"}}
+]}`,
+		},
+		{
+			name:          "invalid JSON",
+			src:           `{"module": {"vpc": {"source": }}}`,
+			expectedError: true,
+		},
+	}
+
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v1.1.0")})
+	}).WithCondition(conditions.All(
+		conditions.SubmoduleMatches("//vpc"),
+		conditions.RevisionMatches(module.Revision("v1.0.0")),
+	))
+	manager := NewManager(Config{}, strategy)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			result, err := manager.updateJSONFileBody([]byte(tc.src), "main.tf.json", &Results{})
+			if tc.expectedError {
+				assert.Equal(true, err != nil)
+				assert.Equal(tc.src, string(result))
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expectedResult, string(result))
+		})
+	}
+}
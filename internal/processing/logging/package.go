@@ -10,3 +10,19 @@ const (
 	WARN
 	ERROR
 )
+
+var levelNames = map[Level]string{
+	TRACE: "trace",
+	DEBUG: "debug",
+	INFO:  "info",
+	WARN:  "warn",
+	ERROR: "error",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+
+	return "unknown"
+}
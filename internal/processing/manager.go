@@ -1,18 +1,27 @@
 package processing
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
-	"io/ioutil"
-	"os"
+	pathpkg "path"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
-	"github.com/maxim-nazarenko/tf-module-update/internal/module"
-	"github.com/maxim-nazarenko/tf-module-update/internal/strategies"
+	"github.com/maxim-nazarenko/tf-module-update/internal/pathmatch"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // ExcludeFileFunc makes a decision if particular file/folder should be excluded
@@ -28,6 +37,47 @@ type Config struct {
 
 	// ExcludeItemsFunc checks if the given item should be excluded or not
 	ExcludeItemsFunc ExcludeFileFunc
+
+	// Terragrunt enables processing of "terraform { source = ... }" blocks in *.hcl files
+	Terragrunt bool
+
+	// Jobs is the number of files processed concurrently, values below 1 mean sequential processing
+	Jobs int
+
+	// Approver, if set, is asked to approve every change, files are processed sequentially then
+	Approver Approver
+
+	// Validator, if set, checks every new source, the block is left untouched and an error is reported if it fails
+	Validator Validator
+
+	// Changelogs, if set, collects changes of the module for every change, they are reported with the change
+	Changelogs ChangelogProvider
+
+	// InterfaceChecker, if set, compares module interface at revisions of every change, breaking differences are reported as warnings
+	InterfaceChecker InterfaceChecker
+
+	// Include, if not empty, limits processing to files matching any of these globs,
+	// globs are matched against paths relative to the walked folder, "**" matches any number of folders
+	Include []string
+
+	// Exclude skips files and folders matching any of these globs, see Include
+	Exclude []string
+
+	// IgnoreFiles holds names of files in .gitignore format which are honoured in every walked folder
+	IgnoreFiles []string
+
+	// MaxDepth limits how deep folders are walked, 1 means only files of the given folders, 0 means no limit
+	MaxDepth int
+
+	// FS is used to read files, the local file system is used when it is nil
+	FS fs.FS
+
+	// Writer is used to write files when Write is set, files are written to the local file system when both
+	// Writer and FS are nil. Writing fails when only FS is given
+	Writer FileWriter
+
+	// Backup makes manager keep original content of every written file next to it, see BackupSuffix
+	Backup bool
 }
 
 // RevisionManager is responsible for managing module source updates
@@ -35,196 +85,648 @@ type RevisionManager struct {
 	config        Config
 	strategy      strategies.Strategy
 	resultFactory *ResultFactory
+	fsys          fs.FS
+	writer        FileWriter
 }
 
 // ProcessPaths processes Terraform in the given paths
 //
-// The process is recursive and checks only files that are not ignored (see ignoredFile())
+// The process is recursive and checks only files that are not ignored (see ignoredFile()).
+// Files are processed concurrently by up to Config.Jobs workers, but per-file results
+// are appended in path order, so the output does not depend on scheduling
 func (m *RevisionManager) ProcessPaths(paths []string, results *Results) {
+	m.ProcessPathsContext(context.Background(), paths, results)
+}
+
+// ProcessPathsContext is the same as ProcessPaths, but stops taking new files once ctx is done
+func (m *RevisionManager) ProcessPathsContext(ctx context.Context, paths []string, results *Results) {
+	files := m.collectFiles(paths, results)
+	fileResults := &Results{}
+	for _, r := range m.processFiles(ctx, files) {
+		if r != nil {
+			fileResults.Append(r)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		results.Append(err)
+	}
+	results.Append(fileResults)
+	results.Append(m.overridesReport(files, fileResults.ModuleCalls()))
+}
+
+// collectFiles walks the given paths and returns sorted list of unique files to process
+func (m *RevisionManager) collectFiles(paths []string, results *Results) []string {
+	include, err := pathmatch.CompileGlobs(m.config.Include)
+	if err != nil {
+		results.Append(err)
+		return nil
+	}
+	exclude, err := pathmatch.CompileGlobs(m.config.Exclude)
+	if err != nil {
+		results.Append(err)
+		return nil
+	}
+
+	files := []string{}
 	var absPath string
-	var err error
 	for _, p := range paths {
-		absPath, err = filepath.Abs(p)
+		absPath, err = m.normalizePath(p)
 		if err != nil {
 			results.Append(err)
 			continue
 		}
-		info, err := os.Stat(absPath)
+		info, err := fs.Stat(m.fsys, absPath)
 		if err != nil {
 			results.Append(err)
 			continue
 		}
 
-		if m.config.ExcludeItemsFunc != nil && !m.config.ExcludeItemsFunc(info) {
+		// root of non-local file system is named ".", it must not be taken for a hidden folder
+		if absPath != "." && m.excluded(info) {
 			continue
 		}
 
-		if len(m.config.ExcludeNames) > 0 && sliceContains(m.config.ExcludeNames, info.Name()) {
+		if info.IsDir() {
+			walk := dirWalk{include: include, exclude: exclude}
+			files = append(files, m.collectDir(absPath, "", 1, walk, results)...)
 			continue
 		}
 
-		if info.IsDir() {
-			m.processDir(absPath, results)
+		// files given explicitly are processed regardless of patterns and ignore files
+		if m.ignoredFile(absPath) {
 			continue
 		}
+		files = append(files, absPath)
+	}
 
-		if m.ignoredFile(absPath) {
+	sort.Strings(files)
+
+	// the same file might be reachable from several paths,
+	// processing it twice concurrently would race on writing
+	unique := files[:0]
+	for i := range files {
+		if i > 0 && files[i] == files[i-1] {
 			continue
 		}
-		results.Append(m.processFile(absPath))
+		unique = append(unique, files[i])
 	}
+
+	return unique
+}
+
+// processFiles runs processFile for each file using a bounded pool of workers
+// and returns results in the same order as files, results of files skipped due to done ctx are nil
+func (m *RevisionManager) processFiles(ctx context.Context, files []string) []*Results {
+	fileResults := make([]*Results, len(files))
+
+	jobs := m.config.Jobs
+	if jobs < 1 || m.config.Approver != nil {
+		jobs = 1
+	}
+	if jobs > len(files) {
+		jobs = len(files)
+	}
+
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fileResults[i] = m.processFile(files[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range files {
+		// select picks randomly among ready cases, so done ctx is checked first
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return fileResults
+}
+
+func (m *RevisionManager) excluded(info fs.FileInfo) bool {
+	if m.config.ExcludeItemsFunc != nil && !m.config.ExcludeItemsFunc(info) {
+		return true
+	}
+
+	return len(m.config.ExcludeNames) > 0 && sliceContains(m.config.ExcludeNames, info.Name())
 }
 
 func (m *RevisionManager) ignoredFile(absPath string) bool {
-	return filepath.Ext(absPath) != ".tf"
+	if isTerraformTestFile(absPath) {
+		return false
+	}
+
+	if m.config.Terragrunt && isTerragruntFile(absPath) {
+		return false
+	}
+
+	switch filepath.Ext(absPath) {
+	case ".tf", ".tofu":
+		return false
+	}
+
+	return !isJSONFile(absPath)
 }
 
-func (m *RevisionManager) processDir(path string, results *Results) {
-	items, err := ioutil.ReadDir(path)
+// isTerragruntFile reports if the file is terragrunt.hcl or one of its *.hcl includes
+func isTerragruntFile(path string) bool {
+	return filepath.Ext(path) == ".hcl" && !isTerraformTestFile(path)
+}
+
+// terragruntInputs returns sorted keys of "inputs" attribute of Terragrunt file,
+// false is returned if they cannot be found out without evaluation or inputs might come from included files
+func terragruntInputs(src []byte, path string) ([]string, bool) {
+	file, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, false
+	}
+
+	body := file.Body.(*hclsyntax.Body)
+	for _, block := range body.Blocks {
+		if block.Type == "include" {
+			return nil, false
+		}
+	}
+
+	keys := []string{}
+	attr, ok := body.Attributes["inputs"]
+	if !ok {
+		return keys, true
+	}
+	object, ok := attr.Expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return nil, false
+	}
+	for _, item := range object.Items {
+		// bare identifiers and quoted strings are evaluated without context, other keys are not known
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || !key.IsKnown() || key.IsNull() || key.Type() != cty.String {
+			return nil, false
+		}
+		keys = append(keys, key.AsString())
+	}
+	sort.Strings(keys)
+
+	return keys, true
+}
+
+// isTerraformTestFile reports if the file belongs to Terraform native test framework
+func isTerraformTestFile(path string) bool {
+	return strings.HasSuffix(path, ".tftest.hcl")
+}
+
+// isSourceBlock reports if the block holds module source in the given file
+//
+// Terraform uses top-level "module" blocks, Terraform tests load helper modules
+// via "run { module { ... } }" and Terragrunt uses top-level "terraform" block
+func (m *RevisionManager) isSourceBlock(path string, block *hclwrite.Block, parents []string) bool {
+	switch {
+	case isTerraformTestFile(path):
+		return block.Type() == "module" && len(parents) == 1 && parents[0] == "run"
+	case m.config.Terragrunt && isTerragruntFile(path):
+		return block.Type() == "terraform" && len(parents) == 0
+	default:
+		return block.Type() == "module" && len(parents) == 0
+	}
+}
+
+// walkBlocks calls f for each block of the body including nested ones,
+// parents holds types of enclosing blocks starting from the outermost
+func walkBlocks(body *hclwrite.Body, parents []string, f func(block *hclwrite.Block, parents []string)) {
+	for _, b := range body.Blocks() {
+		f(b, parents)
+		walkBlocks(b.Body(), append(parents[:len(parents):len(parents)], b.Type()), f)
+	}
+}
+
+// dirWalk holds patterns and ignore files which apply to the folder being walked
+type dirWalk struct {
+	include []*pathmatch.Glob
+	exclude []*pathmatch.Glob
+	ignores []*pathmatch.IgnoreList
+}
+
+// collectDir returns files to process in the folder and its subfolders,
+// rel is slash-separated path of the folder relative to the walked path and depth is its level starting from 1
+func (m *RevisionManager) collectDir(path string, rel string, depth int, walk dirWalk, results *Results) []string {
+	if m.config.MaxDepth > 0 && depth > m.config.MaxDepth {
+		return nil
+	}
+
+	entries, err := fs.ReadDir(m.fsys, path)
 	if err != nil {
 		results.Append(err)
-		return
+		return nil
 	}
 
+	for _, name := range m.config.IgnoreFiles {
+		list, err := m.readIgnoreFile(m.joinPath(path, name), rel)
+		if err != nil {
+			results.Append(err)
+			continue
+		}
+		if list != nil {
+			walk.ignores = append(walk.ignores[:len(walk.ignores):len(walk.ignores)], list)
+		}
+	}
+
+	files := []string{}
 	var itemPath string
-	for _, item := range items {
-		if m.config.ExcludeItemsFunc != nil && !m.config.ExcludeItemsFunc(item) {
+	for _, entry := range entries {
+		item, err := entry.Info()
+		if err != nil {
+			results.Append(err)
+			continue
+		}
+		if m.excluded(item) {
 			continue
 		}
 
-		if len(m.config.ExcludeNames) > 0 && sliceContains(m.config.ExcludeNames, item.Name()) {
+		itemRel := pathpkg.Join(rel, item.Name())
+		// the standard Terraform .gitignore lists override files, but they change effective sources of calls
+		ignored := pathmatch.Ignored(walk.ignores, itemRel, item.IsDir()) && (item.IsDir() || !isOverrideFile(item.Name()))
+		if ignored || pathmatch.MatchAny(walk.exclude, itemRel) {
 			continue
 		}
 
-		itemPath = filepath.Join(path, item.Name())
+		itemPath = m.joinPath(path, item.Name())
 		if item.IsDir() {
-			m.processDir(itemPath, results)
+			files = append(files, m.collectDir(itemPath, itemRel, depth+1, walk, results)...)
 			continue
 		}
 		if m.ignoredFile(itemPath) {
 			continue
 		}
-		results.Append(m.processFile(itemPath))
+		if len(walk.include) > 0 && !pathmatch.MatchAny(walk.include, itemRel) {
+			continue
+		}
+		files = append(files, itemPath)
+	}
+
+	return files
+}
+
+// readIgnoreFile parses ignore file of the folder, nil is returned if the file does not exist
+func (m *RevisionManager) readIgnoreFile(path string, rel string) (*pathmatch.IgnoreList, error) {
+	content, err := fs.ReadFile(m.fsys, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := pathmatch.ParseIgnore(rel, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
 	}
+
+	return list, nil
 }
 
 func (m *RevisionManager) processFile(fileName string) *Results {
 
 	results := &Results{}
 
-	f, err := os.Open(fileName)
+	src, err := fs.ReadFile(m.fsys, fileName)
 	if err != nil {
 		results.Append(err)
 		return results
 	}
 
-	defer f.Close()
-	src, err := io.ReadAll(f)
+	updatedFileBody, err := m.processSource(src, fileName, fileName, results)
 	if err != nil {
 		results.Append(err)
 		return results
 	}
-	normalizedPath, err := filepath.Abs(fileName)
+
+	if m.config.Write && string(updatedFileBody) != string(src) {
+		written, err := m.writeFile(fileName, src, updatedFileBody)
+		if err != nil {
+			results.Append(err)
+			return results
+		}
+		results.Append(written)
+	}
+
+	return results
+}
+
+// writeFile replaces original content of the file with the updated one, keeping a backup if configured
+//
+// The file is not written if it was changed by someone else since it had been read
+func (m *RevisionManager) writeFile(fileName string, original []byte, updated []byte) (Written, error) {
+	current, err := fs.ReadFile(m.fsys, fileName)
 	if err != nil {
-		results.Append(err)
-		return results
+		return Written{}, err
+	}
+	if contentHash(current) != contentHash(original) {
+		return Written{}, fmt.Errorf("%s: file was changed since it had been read, not writing it", fileName)
 	}
 
-	infileHeader := m.resultFactory.Info("In file " + fileName + ":")
-	bodyResults := &Results{}
-	updatedFileBody, err := m.updateFileBody(src, normalizedPath, bodyResults)
-	if string(updatedFileBody) != string(src) {
-		results.Append(infileHeader)
+	written := Written{File: fileName, SHA256: contentHash(updated)}
+	if m.config.Backup {
+		written.Backup = fileName + BackupSuffix
+		write := m.writer.WriteFile
+		if backupWriter, ok := m.writer.(BackupWriter); ok {
+			write = func(name string, data []byte) error {
+				return backupWriter.WriteBackup(name, fileName, data)
+			}
+		}
+		if err := write(written.Backup, original); err != nil {
+			return Written{}, err
+		}
 	}
-	results.Append(bodyResults)
+
+	if err := m.writer.WriteFile(fileName, updated); err != nil {
+		return Written{}, err
+	}
+
+	return written, nil
+}
+
+// contentHash returns hex encoded SHA-256 hash of the content
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// ProcessSource updates module sources in the content read from r and writes the updated content to w
+//
+// The path is used only to pick the syntax and in messages, nothing is read from it or written to it.
+// The original content is written when it cannot be processed, so r might be safely piped through
+func (m *RevisionManager) ProcessSource(r io.Reader, w io.Writer, path string, results *Results) {
+	src, err := io.ReadAll(r)
 	if err != nil {
 		results.Append(err)
-		return results
+		return
 	}
 
-	if m.config.Write {
-		if err = ioutil.WriteFile(normalizedPath, updatedFileBody, 0644); err != nil {
-			results.Append(err)
-			return results
-		}
+	updated, err := m.processSource(src, path, path, results)
+	if err != nil {
+		results.Append(err)
 	}
 
-	return results
+	if _, err := w.Write(updated); err != nil {
+		results.Append(err)
+	}
+}
+
+// processSource updates content of a single file without touching the file system
+// and returns the updated content, or the original one in case of an error
+func (m *RevisionManager) processSource(src []byte, normalizedPath string, displayName string, results *Results) ([]byte, error) {
+	updateBody := m.updateFileBody
+	if isJSONFile(normalizedPath) {
+		updateBody = m.updateJSONFileBody
+	}
+
+	bodyResults := &Results{}
+	updatedFileBody, err := updateBody(src, normalizedPath, bodyResults)
+	if string(updatedFileBody) != string(src) || len(bodyResults.Skips()) > 0 {
+		results.Append(m.resultFactory.Info("In file " + displayName + ":"))
+	}
+	results.Append(bodyResults)
+	if err != nil {
+		return src, err
+	}
+
+	return updatedFileBody, nil
 }
 
 func (m *RevisionManager) updateFileBody(src []byte, normalizedPath string, results *Results) ([]byte, error) {
 	parsed, diags := hclwrite.ParseConfig(src, normalizedPath, hcl.InitialPos)
 	if diags.HasErrors() {
-		results.Append(diags.Errs())
-		return src, errors.New("parsing HCL syntax failed")
+		return src, fmt.Errorf("parsing HCL syntax failed: %s", diags.Error())
 	}
 	parsedBody := parsed.Body()
+	strategy := m.strategyFor(normalizedPath)
 
-	blocks := parsedBody.Blocks()
-
-	for _, b := range blocks {
-		// we can process only modules
-		if b.Type() != "module" {
-			continue
+	walkBlocks(parsedBody, nil, func(b *hclwrite.Block, parents []string) {
+		// we can process only blocks with module source
+		if !m.isSourceBlock(normalizedPath, b, parents) {
+			return
 		}
 		// there must be a "source" attribute to update
 		if _, ok := b.Body().Attributes()["source"]; !ok {
-			continue
+			return
 		}
 
-		results.Append(m.processBlock(b))
-	}
+		name := ""
+		if len(b.Labels()) > 0 {
+			name = b.Labels()[0]
+		}
+		attributes := []string{}
+		for attr := range b.Body().Attributes() {
+			attributes = append(attributes, attr)
+		}
+		call := newModuleCall(
+			normalizedPath, name, append(parents, b.Type()),
+			attributeString(b.Body().GetAttribute("source")),
+			attributeString(b.Body().GetAttribute("version")),
+			attributes,
+		)
+		if b.Type() == "terraform" {
+			inputs, known := terragruntInputs(src, normalizedPath)
+			call.Arguments, call.ArgumentsUnknown = inputs, !known
+		}
+
+		results.Append(m.processBlock(b, call, strategy))
+
+		call.Source = attributeString(b.Body().GetAttribute("source"))
+		results.Append(call)
+	})
 
 	return parsed.Bytes(), nil
 }
 
-func (m *RevisionManager) processBlock(block *hclwrite.Block) Results {
-	results := Results{}
+func (m *RevisionManager) processBlock(block *hclwrite.Block, call ModuleCall, strategy strategies.Strategy) *Results {
+	results := &Results{}
 	// just a sanity check
 	sourceAttr := block.Body().GetAttribute("source")
-	if block.Type() != "module" || sourceAttr == nil {
-		results.Append(errors.New("current block is not a module or does not have source attribute"))
+	if sourceAttr == nil {
+		results.Append(errors.New("current block does not have source attribute"))
 		return results
 	}
 
+	// only plain quoted strings are supported, templates like "${local.base}//vpc" are left untouched
 	exprTokens := sourceAttr.Expr().BuildTokens(nil)
-	if exprTokens[1].Type != hclsyntax.TokenQuotedLit {
+	if len(exprTokens) != 3 || exprTokens[1].Type != hclsyntax.TokenQuotedLit {
+		results.Append(m.resultFactory.Debug("skipping source which is not a plain string: " + string(exprTokens.Bytes())))
 		return results
 	}
 
-	source, err := module.ParseSource(string(exprTokens[1].Bytes))
+	directives, err := blockDirectives(block)
 	if err != nil {
-		results.Append(err)
+		results.Append(m.resultFactory.Warn(fmt.Sprintf("skipping module %q in %s: %s", call.Name, call.File, err)))
 		return results
 	}
 
-	if !m.strategy.Decide(source) {
-		results.Append(m.resultFactory.Debug("skipping source due to updater decision: " + source.String()))
+	newSource, sourceResults := m.updateSource(call, strategy, directives)
+	results.Append(sourceResults)
+	if newSource == string(exprTokens[1].Bytes) {
 		return results
 	}
 
-	newSource := m.strategy.Apply(source)
+	exprTokens[1] = &hclwrite.Token{
+		Type:         exprTokens[1].Type,
+		Bytes:        []byte(newSource),
+		SpacesBefore: exprTokens[1].SpacesBefore,
+	}
+
+	block.Body().SetAttributeRaw("source",
+		exprTokens,
+	)
+
+	return results
+}
+
+// updateSource runs the strategy against module source of the call
+// and returns updated string, or the original one if no update is needed
+func (m *RevisionManager) updateSource(call ModuleCall, strategy strategies.Strategy, directives []directive) (string, *Results) {
+	results := &Results{}
+	rawSource := call.Source
+
+	// sources of other kinds, e.g. "s3::" or scp-like git addresses, cannot be targeted by strategies
+	source, err := module.ParseSource(rawSource)
+	if err != nil {
+		results.Append(m.resultFactory.Debug(fmt.Sprintf("skipping source which cannot be parsed: %s: %s", rawSource, err)))
+		return rawSource, results
+	}
+
+	if !strategy.Decide(source) {
+		results.Append(m.resultFactory.Debug("skipping source due to updater decision: " + source.String()))
+		return rawSource, results
+	}
+
+	newSource := strategy.Apply(source)
 
 	if source.String() == newSource.String() {
-		return results
+		if skipper, ok := strategy.(strategies.Skipper); ok {
+			reason, err := skipper.SkipReason(source)
+			if err != nil {
+				results.Append(fmt.Errorf("%s: module %q: cannot update %s: %s", call.File, call.Name, source, err))
+				return rawSource, results
+			}
+			if reason != "" {
+				results.Append(
+					m.resultFactory.Info("  skipped "+source.String()+" due to "+reason),
+					Skip{Call: call, Reason: reason},
+				)
+			}
+		}
+		return rawSource, results
 	}
 
-	exprTokens[1] = &hclwrite.Token{
-		Type:         exprTokens[1].Type,
-		Bytes:        []byte(newSource.String()),
-		SpacesBefore: exprTokens[1].SpacesBefore,
+	// parsing does not keep every spelling, e.g. "github.com/..." shorthand, so the new source
+	// might be written exactly as the original one although they differ after parsing
+	if newSource.String() == rawSource {
+		return rawSource, results
+	}
+
+	reason := skipReason(directives, newSource)
+	if reason == "" && m.config.Validator != nil {
+		if err := m.config.Validator.Validate(newSource); err != nil {
+			results.Append(fmt.Errorf("%s: module %q: not updating to %s: %s", call.File, call.Name, newSource, err))
+			return rawSource, results
+		}
+	}
+	skip := func(reason string) (string, *Results) {
+		results.Append(
+			m.resultFactory.Info("  skipped "+source.String()+" due to "+reason),
+			Skip{Call: call, Reason: reason},
+		)
+		return rawSource, results
+	}
+	if reason != "" {
+		return skip(reason)
+	}
+
+	// details are collected before approval, so the approver decides knowing the changelog and interface changes
+	change := Change{Call: call, Before: source, After: newSource}
+	details := &Results{}
+	if explainer, ok := strategy.(strategies.Explainer); ok {
+		change.Explanation = explainer.Explain(source)
+		for _, reason := range change.Explanation {
+			details.Append(m.resultFactory.Info("    " + reason))
+		}
+	}
+
+	if m.config.Changelogs != nil {
+		changelog, err := m.config.Changelogs.Changelog(source, newSource)
+		if err != nil {
+			details.Append(m.resultFactory.Warn("    changelog is not available: " + err.Error()))
+		}
+		for _, subject := range changelog.Commits {
+			details.Append(m.resultFactory.Info("    * " + subject))
+		}
+		change.Changelog = changelog
+	}
+	if m.config.InterfaceChecker != nil && call.ArgumentsUnknown {
+		details.Append(m.resultFactory.Info("    interface is not checked as inputs of the call are not known"))
+	} else if m.config.InterfaceChecker != nil {
+		findings, err := m.config.InterfaceChecker.CheckInterface(change)
+		if err != nil {
+			details.Append(m.resultFactory.Warn("    interface cannot be checked: " + err.Error()))
+		}
+		for _, f := range findings {
+			if f.Breaking {
+				details.Append(m.resultFactory.Warn("    breaking: " + f.Message))
+			} else {
+				details.Append(m.resultFactory.Info("    interface: " + f.Message))
+			}
+		}
+		change.Interface = findings
+	}
+
+	if m.config.Approver != nil && !m.config.Approver.Approve(change) {
+		return skip("not approved")
 	}
 
 	results.Append(
 		m.resultFactory.Info("  - "+source.String()),
 		m.resultFactory.Info("  + "+newSource.String()),
+		details,
 	)
+	results.Append(change)
 
-	block.Body().SetAttributeRaw("source",
-		exprTokens,
-	)
+	return newSource.String(), results
+}
 
-	return results
+// strategyFor returns strategy to use for the given file
+func (m *RevisionManager) strategyFor(path string) strategies.Strategy {
+	if fileStrategy, ok := m.strategy.(strategies.FileStrategy); ok {
+		return fileStrategy.ForFile(path)
+	}
+
+	return m.strategy
+}
+
+// attributeString returns value of attribute if it is a plain string, or its expression as is otherwise
+func attributeString(attr *hclwrite.Attribute) string {
+	if attr == nil {
+		return ""
+	}
+
+	value := strings.TrimSpace(string(attr.Expr().BuildTokens(nil).Bytes()))
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+
+	return value
 }
 
 func sliceContains(slice []string, s string) bool {
@@ -237,5 +739,22 @@ func sliceContains(slice []string, s string) bool {
 }
 
 func NewManager(config Config, strategy strategies.Strategy) *RevisionManager {
-	return &RevisionManager{config: config, strategy: strategy, resultFactory: NewResultFactory()}
+	manager := &RevisionManager{
+		config:        config,
+		strategy:      strategy,
+		resultFactory: NewResultFactory(),
+		fsys:          config.FS,
+		writer:        config.Writer,
+	}
+	if manager.writer == nil {
+		manager.writer = noWriter{}
+		if manager.fsys == nil {
+			manager.writer = osWriter{}
+		}
+	}
+	if manager.fsys == nil {
+		manager.fsys = osFS{}
+	}
+
+	return manager
 }
@@ -0,0 +1,73 @@
+package processing
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/internal/processing/logging"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+)
+
+const benchModuleBlock = `
+module "vpc_%d" {
+  source = "git::https://github.com/example-org/terraform-modules.git//src/vpc?ref=v1.2.3"
+
+  name = "vpc-%d"
+  cidr = "10.0.0.0/16"
+}
+`
+
+// generateTree creates dirs*filesPerDir Terraform files with a few module blocks in each
+func generateTree(b *testing.B, dirs, filesPerDir int) string {
+	b.Helper()
+
+	root := b.TempDir()
+	for d := 0; d < dirs; d++ {
+		dir := filepath.Join(root, fmt.Sprintf("stack-%03d", d))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatal(err)
+		}
+		for f := 0; f < filesPerDir; f++ {
+			content := ""
+			for i := 0; i < 5; i++ {
+				content += fmt.Sprintf(benchModuleBlock, i, i)
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("main-%03d.tf", f)), []byte(content), 0644); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	return root
+}
+
+func benchmarkProcessPaths(b *testing.B, jobs int) {
+	root := generateTree(b, 50, 20)
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v2.0.0")})
+	}).WithCondition(conditions.RevisionMatches(module.Revision("v1.2.3")))
+	manager := NewManager(Config{Jobs: jobs, ExcludeItemsFunc: DefaultExclusionFunc}, strategy)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		manager.ProcessPaths([]string{root}, NewResults(logging.INFO))
+	}
+}
+
+func BenchmarkProcessPathsSequential(b *testing.B) {
+	benchmarkProcessPaths(b, 1)
+}
+
+func BenchmarkProcessPaths4Jobs(b *testing.B) {
+	benchmarkProcessPaths(b, 4)
+}
+
+func BenchmarkProcessPathsNumCPUJobs(b *testing.B) {
+	benchmarkProcessPaths(b, runtime.NumCPU())
+}
@@ -0,0 +1,658 @@
+package processing
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/internal/processing/logging"
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+)
+
+func TestUpdateFileBody(t *testing.T) {
+	testCases := []struct {
+		name           string
+		config         Config
+		path           string
+		src            string
+		expectedResult string
+	}{
+		{
+			name: "module block",
+			path: "main.tf",
+			src: `module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`,
+			expectedResult: `module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0"
+}
+`,
+		},
+		{
+			name: "template source is not changed",
+			path: "main.tf",
+			src: `module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0${local.suffix}"
+}
+`,
+			expectedResult: `module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0${local.suffix}"
+}
+`,
+		},
+		{
+			name: "ignore directive above the block",
+			path: "main.tf",
+			src: `# tf-module-update:ignore
+module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`,
+			expectedResult: `# tf-module-update:ignore
+module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`,
+		},
+		{
+			name: "constraint directive on the source line",
+			path: "main.tf",
+			src: `module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" # tf-module-update: constraint="~> 1.0.0"
+}
+
+module "vpc2" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" # tf-module-update: constraint="~> 1.0"
+}
+`,
+			expectedResult: `module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" # tf-module-update: constraint="~> 1.0.0"
+}
+
+module "vpc2" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0" # tf-module-update: constraint="~> 1.0"
+}
+`,
+		},
+		{
+			name: "invalid directive leaves the block untouched",
+			path: "main.tf",
+			src: `module "vpc" {
+  # tf-module-update: pin="v1.0.0"
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`,
+			expectedResult: `module "vpc" {
+  # tf-module-update: pin="v1.0.0"
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`,
+		},
+		{
+			name:   "terragrunt block",
+			config: Config{Terragrunt: true},
+			path:   "terragrunt.hcl",
+			src: `terraform {
+  source = "git::ssh://git@github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+
+module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`,
+			expectedResult: `terraform {
+  source = "git::ssh://git@github.com/example-org/modules.git//vpc?ref=v1.1.0"
+}
+
+module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`,
+		},
+		{
+			name:   "terragrunt registry source",
+			config: Config{Terragrunt: true},
+			path:   "terragrunt.hcl",
+			src: `terraform {
+  source = "tfr:///example-org/modules/aws//vpc?version=v1.0.0"
+}
+`,
+			expectedResult: `terraform {
+  source = "tfr:///example-org/modules/aws//vpc?version=v1.1.0"
+}
+`,
+		},
+		{
+			name: "terraform test run blocks",
+			path: "tests/vpc.tftest.hcl",
+			src: `run "setup" {
+  module {
+    source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+  }
+}
+
+run "check" {
+  command = plan
+
+  assert {
+    condition     = true
+    error_message = "never fails"
+  }
+}
+`,
+			expectedResult: `run "setup" {
+  module {
+    source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0"
+  }
+}
+
+run "check" {
+  command = plan
+
+  assert {
+    condition     = true
+    error_message = "never fails"
+  }
+}
+`,
+		},
+		{
+			name:   "terraform test files are not treated as terragrunt",
+			config: Config{Terragrunt: true},
+			path:   "tests/vpc.tftest.hcl",
+			src: `terraform {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`,
+			expectedResult: `terraform {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+`,
+		},
+	}
+
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v1.1.0")})
+	}).WithCondition(conditions.All(
+		conditions.SubmoduleMatches("//vpc"),
+		conditions.RevisionMatches(module.Revision("v1.0.0")),
+	))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			manager := NewManager(tc.config, strategy)
+
+			result, err := manager.updateFileBody([]byte(tc.src), tc.path, &Results{})
+			assert.NoError(err)
+			assert.Equal(tc.expectedResult, string(result))
+		})
+	}
+}
+
+func TestProcessSource(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		src            string
+		expectedResult string
+		expectedError  bool
+	}{
+		{
+			name:           "hcl",
+			path:           "main.tf",
+			src:            `module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }`,
+			expectedResult: `module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0" }`,
+		},
+		{
+			name:           "json is picked by path",
+			path:           "main.tf.json",
+			src:            `{"module": {"vpc": {"source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"}}}`,
+			expectedResult: `{"module": {"vpc": {"source": "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0"}}}`,
+		},
+		{
+			name:           "invalid syntax is written as is",
+			path:           "main.tf",
+			src:            `module "vpc" {`,
+			expectedResult: `module "vpc" {`,
+			expectedError:  true,
+		},
+	}
+
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v1.1.0")})
+	}).WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0")))
+	manager := NewManager(Config{}, strategy)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			out := &bytes.Buffer{}
+			results := &Results{}
+
+			manager.ProcessSource(strings.NewReader(tc.src), out, tc.path, results)
+			assert.Equal(tc.expectedResult, out.String())
+			assert.Equal(tc.expectedError, results.HasErrors())
+		})
+	}
+}
+
+// validatorFunc adapts a function to Validator interface
+type validatorFunc func(module.Source) error
+
+func (f validatorFunc) Validate(s module.Source) error {
+	return f(s)
+}
+
+func TestUnparsedSources(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v1.1.0")})
+	}).WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0")))
+	manager := NewManager(Config{}, strategy)
+
+	src := `module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }
+module "dns" { source = "s3::https://s3-eu-west-1.amazonaws.com/example-org/dns.zip" }
+module "eks" { source = "git@github.com:example-org/modules.git//eks?ref=v1.0.0" }
+`
+	out := &bytes.Buffer{}
+	results := &Results{}
+	manager.ProcessSource(strings.NewReader(src), out, "main.tf", results)
+
+	assert.Equal(`module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0" }
+module "dns" { source = "s3::https://s3-eu-west-1.amazonaws.com/example-org/dns.zip" }
+module "eks" { source = "git@github.com:example-org/modules.git//eks?ref=v1.0.0" }
+`, out.String())
+	assert.Equal(false, results.HasErrors())
+	assert.Equal(1, len(results.Changes()))
+	// calls are still collected, so listing commands see every source
+	assert.Equal(3, len(results.ModuleCalls()))
+}
+
+func TestSourceWrittenAsOriginal(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	policy, err := module.PolicyFromString("minimal")
+	assert.NoError(err)
+	manager := NewManager(Config{}, strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return module.Normalize(s, policy)
+	}).WithCondition(func(module.Source) bool { return true }))
+
+	src := `module "vpc" { source = "github.com/example-org/modules//vpc?ref=v1.0.0" }
+module "dns" { source = "github.com/example-org/modules//dns/?ref=v1.0.0" }
+`
+	out := &bytes.Buffer{}
+	results := &Results{}
+	manager.ProcessSource(strings.NewReader(src), out, "main.tf", results)
+
+	assert.Equal(`module "vpc" { source = "github.com/example-org/modules//vpc?ref=v1.0.0" }
+module "dns" { source = "github.com/example-org/modules//dns?ref=v1.0.0" }
+`, out.String())
+	assert.Equal(1, len(results.Changes()))
+}
+
+func TestValidator(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v1.1.0")})
+	}).WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0")))
+	validated := []string{}
+	manager := NewManager(Config{Validator: validatorFunc(func(s module.Source) error {
+		validated = append(validated, s.String())
+		if s.Submodule == "//dns" {
+			return errors.New("submodule dns does not exist")
+		}
+		return nil
+	})}, strategy)
+
+	src := `module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }
+module "dns" { source = "git::https://github.com/example-org/modules.git//dns?ref=v1.0.0" }
+module "eks" { source = "git::https://github.com/example-org/modules.git//eks?ref=v0.9.0" }
+`
+	out := &bytes.Buffer{}
+	results := &Results{}
+	manager.ProcessSource(strings.NewReader(src), out, "main.tf", results)
+
+	assert.Equal(`module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0" }
+module "dns" { source = "git::https://github.com/example-org/modules.git//dns?ref=v1.0.0" }
+module "eks" { source = "git::https://github.com/example-org/modules.git//eks?ref=v0.9.0" }
+`, out.String())
+	// only new sources are validated
+	assert.Equal([]string{
+		"git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0",
+		"git::https://github.com/example-org/modules.git//dns?ref=v1.1.0",
+	}, validated)
+	assert.Equal(1, len(results.Errors()))
+	assert.Equal(`main.tf: module "dns": not updating to git::https://github.com/example-org/modules.git//dns?ref=v1.1.0: submodule dns does not exist`, results.Errors()[0].Error())
+	assert.Equal(1, len(results.Changes()))
+}
+
+// changelogFunc adapts a function to ChangelogProvider interface
+type changelogFunc func(before, after module.Source) (Changelog, error)
+
+func (f changelogFunc) Changelog(before, after module.Source) (Changelog, error) {
+	return f(before, after)
+}
+
+func TestChangelogs(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v1.1.0")})
+	}).WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0")))
+	manager := NewManager(Config{Changelogs: changelogFunc(func(before, after module.Source) (Changelog, error) {
+		if after.Submodule == "//dns" {
+			return Changelog{}, errors.New("ref v1.0.0 is not found")
+		}
+		return Changelog{Commits: []string{"Add " + string(before.Revision) + ".." + string(after.Revision)}}, nil
+	})}, strategy)
+
+	src := `module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }
+module "dns" { source = "git::https://github.com/example-org/modules.git//dns?ref=v1.0.0" }
+`
+	results := NewResults(logging.INFO)
+	manager.ProcessSource(strings.NewReader(src), &bytes.Buffer{}, "main.tf", results)
+
+	changes := results.Changes()
+	assert.Equal(2, len(changes))
+	assert.Equal(Changelog{Commits: []string{"Add v1.0.0..v1.1.0"}}, changes[0].Changelog)
+	assert.Equal(Changelog{}, changes[1].Changelog)
+	// a changelog which is not available does not prevent the update
+	assert.Equal(false, results.HasErrors())
+	assert.Equal(`In file main.tf:
+  - git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0
+  + git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0
+    * Add v1.0.0..v1.1.0
+  - git::https://github.com/example-org/modules.git//dns?ref=v1.0.0
+  + git::https://github.com/example-org/modules.git//dns?ref=v1.1.0
+    changelog is not available: ref v1.0.0 is not found`, results.String())
+}
+
+func TestModuleCallArguments(t *testing.T) {
+	testCases := []struct {
+		name            string
+		path            string
+		src             string
+		expectedResult  []string
+		expectedUnknown bool
+	}{
+		{
+			name: "hcl",
+			path: "main.tf",
+			src: `module "vpc" {
+  source     = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+  count      = 1
+  name       = "main"
+  cidr_block = "10.0.0.0/16"
+  depends_on = [module.dns]
+}`,
+			expectedResult: []string{"cidr_block", "name"},
+		},
+		{
+			name:           "json",
+			path:           "main.tf.json",
+			src:            `{"module": {"vpc": {"//": "comment", "source": "./vpc", "for_each": {}, "name": "main", "cidr_block": "10.0.0.0/16"}}}`,
+			expectedResult: []string{"cidr_block", "name"},
+		},
+		{
+			name: "terraform test helper module",
+			path: "main.tftest.hcl",
+			src: `run "setup" {
+  module {
+    source = "./setup"
+  }
+}`,
+			expectedResult: nil,
+		},
+		{
+			name: "terragrunt inputs",
+			path: "terragrunt.hcl",
+			src: `terraform {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+
+inputs = {
+  name         = "main"
+  "cidr_block" = "10.0.0.0/16"
+}`,
+			expectedResult: []string{"cidr_block", "name"},
+		},
+		{
+			name:           "terragrunt without inputs",
+			path:           "terragrunt.hcl",
+			src:            `terraform { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }`,
+			expectedResult: []string{},
+		},
+		{
+			name: "terragrunt inputs might come from included file",
+			path: "terragrunt.hcl",
+			src: `include "root" {
+  path = find_in_parent_folders()
+}
+
+terraform { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }
+
+inputs = { name = "main" }`,
+			expectedUnknown: true,
+		},
+		{
+			name: "terragrunt inputs built with function",
+			path: "terragrunt.hcl",
+			src: `terraform { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }
+
+inputs = merge(local.common, { name = "main" })`,
+			expectedUnknown: true,
+		},
+	}
+
+	manager := NewManager(Config{Terragrunt: true}, strategies.NewStrictUpdater(nil))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			results := &Results{}
+
+			manager.ProcessSource(strings.NewReader(tc.src), &bytes.Buffer{}, tc.path, results)
+			assert.Equal(false, results.HasErrors())
+			assert.Equal(1, len(results.ModuleCalls()))
+			assert.Equal(tc.expectedResult, results.ModuleCalls()[0].Arguments)
+			assert.Equal(tc.expectedUnknown, results.ModuleCalls()[0].ArgumentsUnknown)
+		})
+	}
+}
+
+// interfaceCheckerFunc adapts a function to InterfaceChecker interface
+type interfaceCheckerFunc func(c Change) ([]InterfaceFinding, error)
+
+func (f interfaceCheckerFunc) CheckInterface(c Change) ([]InterfaceFinding, error) {
+	return f(c)
+}
+
+func TestInterfaceChecker(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v2.0.0")})
+	}).WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0")))
+	manager := NewManager(Config{InterfaceChecker: interfaceCheckerFunc(func(c Change) ([]InterfaceFinding, error) {
+		if c.Call.Name == "dns" {
+			return nil, errors.New("ref v2.0.0 is not found")
+		}
+		return []InterfaceFinding{
+			{Kind: "removed-variable", Name: c.Call.Arguments[0], Breaking: true, Message: "variable " + c.Call.Arguments[0] + " is removed"},
+			{Kind: "removed-output", Name: "id", Message: "output id is removed"},
+		}, nil
+	})}, strategy)
+
+	src := `module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+  legacy = true
+}
+module "dns" { source = "git::https://github.com/example-org/modules.git//dns?ref=v1.0.0" }
+`
+	results := NewResults(logging.INFO)
+	manager.ProcessSource(strings.NewReader(src), &bytes.Buffer{}, "main.tf", results)
+
+	changes := results.Changes()
+	assert.Equal(2, len(changes))
+	assert.Equal(2, len(changes[0].Interface))
+	assert.Equal(0, len(changes[1].Interface))
+	// findings do not prevent the update
+	assert.Equal(false, results.HasErrors())
+	assert.Equal(`In file main.tf:
+  - git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0
+  + git::https://github.com/example-org/modules.git//vpc?ref=v2.0.0
+    breaking: variable legacy is removed
+    interface: output id is removed
+  - git::https://github.com/example-org/modules.git//dns?ref=v1.0.0
+  + git::https://github.com/example-org/modules.git//dns?ref=v2.0.0
+    interface cannot be checked: ref v2.0.0 is not found`, results.String())
+}
+
+func TestInterfaceCheckerUnknownInputs(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+		return s.Merge(module.Source{Revision: module.Revision("v2.0.0")})
+	}).WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0")))
+	checked := 0
+	manager := NewManager(Config{Terragrunt: true, InterfaceChecker: interfaceCheckerFunc(func(c Change) ([]InterfaceFinding, error) {
+		checked++
+		return nil, nil
+	})}, strategy)
+
+	src := `include "root" {
+  path = find_in_parent_folders()
+}
+
+terraform { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }
+`
+	results := NewResults(logging.INFO)
+	manager.ProcessSource(strings.NewReader(src), &bytes.Buffer{}, "terragrunt.hcl", results)
+
+	assert.Equal(0, checked)
+	assert.Equal(1, len(results.Changes()))
+	assert.Equal(`In file terragrunt.hcl:
+  - git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0
+  + git::https://github.com/example-org/modules.git//vpc?ref=v2.0.0
+    interface is not checked as inputs of the call are not known`, results.String())
+}
+
+// approverFunc adapts a function to Approver interface
+type approverFunc func(c Change) bool
+
+func (f approverFunc) Approve(c Change) bool {
+	return f(c)
+}
+
+// explainingStrategy gives the same explanation for every source
+type explainingStrategy struct {
+	strategies.Strategy
+	explanation []string
+}
+
+func (s explainingStrategy) Explain(module.Source) []string {
+	return s.explanation
+}
+
+func TestApproverSeesDetails(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	strategy := explainingStrategy{
+		Strategy: strategies.NewStrictUpdater(func(s module.Source) module.Source {
+			return s.Merge(module.Source{Revision: module.Revision("v2.0.0")})
+		}).WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0"))),
+		explanation: []string{"v3.0.0 is held back"},
+	}
+	approved := []Change{}
+	manager := NewManager(Config{
+		Changelogs: changelogFunc(func(before, after module.Source) (Changelog, error) {
+			return Changelog{Commits: []string{"Remove legacy variable"}}, nil
+		}),
+		InterfaceChecker: interfaceCheckerFunc(func(c Change) ([]InterfaceFinding, error) {
+			return []InterfaceFinding{{Kind: "removed-variable", Name: "legacy", Breaking: true, Message: "variable legacy is removed"}}, nil
+		}),
+		Approver: approverFunc(func(c Change) bool {
+			approved = append(approved, c)
+			return c.Call.Name == "vpc"
+		}),
+	}, strategy)
+
+	src := `module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }
+module "dns" { source = "git::https://github.com/example-org/modules.git//dns?ref=v1.0.0" }
+`
+	results := NewResults(logging.INFO)
+	manager.ProcessSource(strings.NewReader(src), &bytes.Buffer{}, "main.tf", results)
+
+	assert.Equal(2, len(approved))
+	assert.Equal([]string{"v3.0.0 is held back"}, approved[0].Explanation)
+	assert.Equal([]string{"Remove legacy variable"}, approved[0].Changelog.Commits)
+	assert.Equal(1, len(approved[0].Interface))
+	assert.Equal(1, len(results.Changes()))
+	assert.Equal([]Skip{{Call: approved[1].Call, Reason: "not approved"}}, results.Skips())
+	assert.Equal(`In file main.tf:
+  - git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0
+  + git::https://github.com/example-org/modules.git//vpc?ref=v2.0.0
+    v3.0.0 is held back
+    * Remove legacy variable
+    breaking: variable legacy is removed
+  skipped git::https://github.com/example-org/modules.git//dns?ref=v1.0.0 due to not approved`, results.String())
+}
+
+// skippingStrategy never changes sources and reports the reason or the error
+type skippingStrategy struct {
+	strategies.Strategy
+	reason string
+	err    error
+}
+
+func (s skippingStrategy) SkipReason(module.Source) (string, error) {
+	return s.reason, s.err
+}
+
+func TestSkipper(t *testing.T) {
+	testCases := []struct {
+		name           string
+		reason         string
+		err            error
+		expectedSkips  []Skip
+		expectedErrors []error
+	}{
+		{
+			name:          "reason is reported",
+			reason:        "held back v1.1.0 released less than 7d ago",
+			expectedSkips: []Skip{{Call: ModuleCall{File: "main.tf", Name: "vpc", Block: "module", Source: "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"}, Reason: "held back v1.1.0 released less than 7d ago"}},
+		},
+		{
+			name:          "nothing to report",
+			expectedSkips: []Skip{},
+		},
+		{
+			name:           "error is not a skip",
+			err:            errors.New("cannot list releases: no mirror folder is given"),
+			expectedSkips:  []Skip{},
+			expectedErrors: []error{errors.New(`main.tf: module "vpc": cannot update git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0: cannot list releases: no mirror folder is given`)},
+		},
+	}
+
+	src := `module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }`
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			strategy := skippingStrategy{
+				Strategy: strategies.NewStrictUpdater(func(s module.Source) module.Source { return s }).
+					WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0"))),
+				reason: tc.reason,
+				err:    tc.err,
+			}
+			results := &Results{}
+
+			NewManager(Config{}, strategy).ProcessSource(strings.NewReader(src), &bytes.Buffer{}, "main.tf", results)
+			assert.Equal(tc.expectedSkips, results.Skips())
+			assert.Equal(len(tc.expectedErrors), len(results.Errors()))
+			for i, err := range tc.expectedErrors {
+				assert.Equal(err.Error(), results.Errors()[i].Error())
+			}
+			assert.Equal(0, len(results.Changes()))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package processing
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+// ModuleCall describes a single block with module source found in a file
+type ModuleCall struct {
+	// File is the path of the file the call is defined in
+	File string
+
+	// Name is the label of module block, empty for blocks without labels like Terragrunt "terraform"
+	Name string
+
+	// Block is the path of block types to the call, e.g. "module" or "run.module"
+	Block string
+
+	// Source is the module source as it is written in the file after processing
+	Source string
+
+	// Version is the version constraint of registry module, if set
+	Version string
+
+	// Arguments holds sorted names of input variables set by the call: arguments of "module" block
+	// without meta-arguments, or keys of "inputs" of the Terragrunt file for "terraform" block
+	Arguments []string
+
+	// ArgumentsUnknown reports that Arguments might miss input variables set by the call,
+	// e.g. Terragrunt inputs merged from included files or built with functions
+	ArgumentsUnknown bool
+}
+
+// Dir returns directory of the file with module call
+func (c ModuleCall) Dir() string {
+	return filepath.Dir(c.File)
+}
+
+// moduleMetaArguments are arguments of "module" block which are not input variables of the module
+var moduleMetaArguments = []string{"source", "version", "count", "for_each", "providers", "depends_on"}
+
+func newModuleCall(file, name string, blockTypes []string, source, version string, attributes []string) ModuleCall {
+	call := ModuleCall{
+		File:    file,
+		Name:    name,
+		Block:   strings.Join(blockTypes, "."),
+		Source:  source,
+		Version: version,
+	}
+
+	if blockTypes[len(blockTypes)-1] == "module" {
+		for _, a := range attributes {
+			if !sliceContains(moduleMetaArguments, a) {
+				call.Arguments = append(call.Arguments, a)
+			}
+		}
+		sort.Strings(call.Arguments)
+	}
+
+	return call
+}
+
+// Change describes update of module source made by strategy
+type Change struct {
+	// Call is the module call as it was before the change
+	Call   ModuleCall
+	Before module.Source
+	After  module.Source
+
+	// Explanation holds reasons given by the strategy for the new source, if any
+	Explanation []string
+
+	// Changelog holds changes of the module between the revisions, if collected
+	Changelog Changelog
+
+	// Interface holds differences of the module interface between the revisions, if checked
+	Interface []InterfaceFinding
+}
+
+// Skip describes module call left untouched although strategy proposed a change
+type Skip struct {
+	Call   ModuleCall
+	Reason string
+}
+
+// Written describes a file written during processing
+type Written struct {
+	File string
+
+	// Backup is the path of the file with original content, empty if backup was not requested
+	Backup string
+
+	// SHA256 is hex encoded hash of the written content
+	SHA256 string
+}
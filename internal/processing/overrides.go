@@ -0,0 +1,122 @@
+package processing
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// configExtensions lists extensions of Terraform and OpenTofu configuration files,
+// longer ones go first so ".tf.json" is not mistaken for ".json"
+var configExtensions = []string{".tofu.json", ".tf.json", ".tofu", ".tf"}
+
+// splitConfigName splits file name into stem and configuration extension, e.g. "main" and ".tf.json"
+func splitConfigName(path string) (string, string) {
+	name := filepath.Base(path)
+	for _, ext := range configExtensions {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext), ext
+		}
+	}
+
+	return name, ""
+}
+
+// isOverrideFile reports if the file is Terraform override file, e.g. override.tf or vpc_override.tf
+func isOverrideFile(path string) bool {
+	stem, ext := splitConfigName(path)
+	if ext == "" {
+		return false
+	}
+
+	return stem == "override" || strings.HasSuffix(stem, "_override")
+}
+
+// tofuCounterpart returns path of OpenTofu file which takes precedence over the given Terraform file
+func tofuCounterpart(path string) string {
+	stem, ext := splitConfigName(path)
+	switch ext {
+	case ".tf":
+		return filepath.Join(filepath.Dir(path), stem+".tofu")
+	case ".tf.json":
+		return filepath.Join(filepath.Dir(path), stem+".tofu.json")
+	}
+
+	return ""
+}
+
+// overridesReport explains which source a module call actually uses
+// when it is redefined in override files or the file is shadowed by OpenTofu one
+func (m *RevisionManager) overridesReport(files []string, calls []ModuleCall) *Results {
+	results := &Results{}
+
+	processed := map[string]bool{}
+	for _, f := range files {
+		processed[f] = true
+	}
+
+	shadowed := map[string]bool{}
+	for _, f := range files {
+		counterpart := tofuCounterpart(f)
+		if counterpart != "" && processed[counterpart] {
+			shadowed[f] = true
+			results.Append(m.resultFactory.Info(fmt.Sprintf("File %s is ignored by OpenTofu in favor of %s", f, counterpart)))
+		}
+	}
+
+	type callKey struct {
+		dir  string
+		name string
+	}
+	keys := []callKey{}
+	bases := map[callKey][]ModuleCall{}
+	overrides := map[callKey][]ModuleCall{}
+	for _, c := range calls {
+		if c.Block != "module" || shadowed[c.File] {
+			continue
+		}
+
+		key := callKey{dir: c.Dir(), name: c.Name}
+		if _, ok := bases[key]; !ok {
+			if _, ok := overrides[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+
+		if isOverrideFile(c.File) {
+			overrides[key] = append(overrides[key], c)
+			continue
+		}
+		bases[key] = append(bases[key], c)
+	}
+
+	for _, key := range keys {
+		keyOverrides := overrides[key]
+		if len(keyOverrides) == 0 {
+			continue
+		}
+
+		// override files are merged in lexicographical order, so the last one wins
+		sort.SliceStable(keyOverrides, func(i, j int) bool {
+			return filepath.Base(keyOverrides[i].File) < filepath.Base(keyOverrides[j].File)
+		})
+		effective := keyOverrides[len(keyOverrides)-1]
+
+		if len(bases[key]) == 0 {
+			results.Append(m.resultFactory.Warn(fmt.Sprintf(
+				"Module %q in %s is overridden in %s but has no base definition",
+				key.name, key.dir, effective.File,
+			)))
+			continue
+		}
+
+		base := bases[key][0]
+		results.Append(m.resultFactory.Info(fmt.Sprintf(
+			"Module %q in %s uses effective source %s from %s, overriding %s from %s",
+			key.name, key.dir, effective.Source, effective.File, base.Source, base.File,
+		)))
+	}
+
+	return results
+}
@@ -0,0 +1,88 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/processing/logging"
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestIsOverrideFile(t *testing.T) {
+	testCases := []struct {
+		path           string
+		expectedResult bool
+	}{
+		{path: "/stack/override.tf", expectedResult: true},
+		{path: "/stack/vpc_override.tf", expectedResult: true},
+		{path: "/stack/vpc_override.tf.json", expectedResult: true},
+		{path: "/stack/vpc_override.tofu", expectedResult: true},
+		{path: "/stack/main.tf", expectedResult: false},
+		{path: "/stack/override_vpc.tf", expectedResult: false},
+		{path: "/stack/override.hcl", expectedResult: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			assert.Equal(tc.expectedResult, isOverrideFile(tc.path))
+		})
+	}
+}
+
+func TestOverridesReport(t *testing.T) {
+	testCases := []struct {
+		name           string
+		files          []string
+		calls          []ModuleCall
+		expectedResult string
+	}{
+		{
+			name:  "no overrides",
+			files: []string{"/stack/main.tf"},
+			calls: []ModuleCall{
+				{File: "/stack/main.tf", Name: "vpc", Block: "module", Source: "vpc?ref=v1"},
+			},
+			expectedResult: "",
+		},
+		{
+			name:  "last override wins",
+			files: []string{"/stack/a_override.tf", "/stack/b_override.tf", "/stack/main.tf"},
+			calls: []ModuleCall{
+				{File: "/stack/b_override.tf", Name: "vpc", Block: "module", Source: "vpc?ref=v3"},
+				{File: "/stack/a_override.tf", Name: "vpc", Block: "module", Source: "vpc?ref=v2"},
+				{File: "/stack/main.tf", Name: "vpc", Block: "module", Source: "vpc?ref=v1"},
+				{File: "/other/main.tf", Name: "vpc", Block: "module", Source: "vpc?ref=v1"},
+			},
+			expectedResult: `Module "vpc" in /stack uses effective source vpc?ref=v3 from /stack/b_override.tf, overriding vpc?ref=v1 from /stack/main.tf`,
+		},
+		{
+			name:  "override without base",
+			files: []string{"/stack/override.tf"},
+			calls: []ModuleCall{
+				{File: "/stack/override.tf", Name: "db", Block: "module", Source: "db?ref=v1"},
+			},
+			expectedResult: `Module "db" in /stack is overridden in /stack/override.tf but has no base definition`,
+		},
+		{
+			name:  "tofu file shadows terraform one",
+			files: []string{"/stack/main.tf", "/stack/main.tofu", "/stack/override.tf"},
+			calls: []ModuleCall{
+				{File: "/stack/main.tf", Name: "vpc", Block: "module", Source: "vpc?ref=v1"},
+				{File: "/stack/main.tofu", Name: "vpc", Block: "module", Source: "vpc?ref=v2"},
+				{File: "/stack/override.tf", Name: "vpc", Block: "module", Source: "vpc?ref=v3"},
+			},
+			expectedResult: `File /stack/main.tf is ignored by OpenTofu in favor of /stack/main.tofu
+Module "vpc" in /stack uses effective source vpc?ref=v3 from /stack/override.tf, overriding vpc?ref=v2 from /stack/main.tofu`,
+		},
+	}
+
+	manager := NewManager(Config{}, nil)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			results := NewResults(logging.INFO)
+			results.Append(manager.overridesReport(tc.files, tc.calls))
+			assert.Equal(tc.expectedResult, results.String())
+		})
+	}
+}
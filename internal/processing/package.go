@@ -11,6 +11,15 @@ var DefaultExclusionNamesList []string = []string{
 	".git",
 }
 
+// DefaultIgnoreFiles contains names of ignore files in .gitignore format honoured by default
+var DefaultIgnoreFiles []string = []string{
+	".gitignore",
+	".tf-module-update-ignore",
+}
+
+// BackupSuffix is appended to the file name to get the name of its backup
+const BackupSuffix = ".bak"
+
 // DefaultExclusionFunc excludes all hidden, e.g. starting with dot ".", folders and files
 var DefaultExclusionFunc ExcludeFileFunc = func(info fs.FileInfo) bool {
 	return !strings.HasPrefix(info.Name(), ".")
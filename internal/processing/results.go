@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 
 	"github.com/maxim-nazarenko/tf-module-update/internal/processing/logging"
 )
@@ -19,14 +20,24 @@ func (p *Result) String() string {
 }
 
 // Results holds a set of Result messages alongside with errors
+//
+// It is safe to append to Results from multiple goroutines
 type Results struct {
+	mu      sync.Mutex
 	errors  []error
 	level   logging.Level
 	results []Result
+	calls   []ModuleCall
+	changes []Change
+	skips   []Skip
+	written []Written
 }
 
 // Append adds more items to the results set which might be rendered later
 func (p *Results) Append(new ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	for _, v := range new {
 		if v == nil {
 			continue
@@ -41,10 +52,23 @@ func (p *Results) Append(new ...interface{}) {
 			p.results = append(p.results, t)
 		case *Result:
 			p.results = append(p.results, *t)
-		case Results:
-			p.results = append(p.results, t.results...)
+		case ModuleCall:
+			p.calls = append(p.calls, t)
+		case Change:
+			p.changes = append(p.changes, t)
+		case Skip:
+			p.skips = append(p.skips, t)
+		case Written:
+			p.written = append(p.written, t)
 		case *Results:
+			t.mu.Lock()
+			p.errors = append(p.errors, t.errors...)
 			p.results = append(p.results, t.results...)
+			p.calls = append(p.calls, t.calls...)
+			p.changes = append(p.changes, t.changes...)
+			p.skips = append(p.skips, t.skips...)
+			p.written = append(p.written, t.written...)
+			t.mu.Unlock()
 		default:
 			log.Fatalf("unsupported result type: %T", t)
 		}
@@ -53,6 +77,9 @@ func (p *Results) Append(new ...interface{}) {
 
 // String renders result records as string
 func (p *Results) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	lines := make([]string, 0)
 	for _, v := range p.results {
 		if v.Level < p.level {
@@ -70,9 +97,60 @@ func (p *Results) String() string {
 
 // HasErrors indicates that there was an error
 func (p *Results) HasErrors() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	return len(p.errors) > 0
 }
 
+// Messages returns all collected messages regardless of the level
+func (p *Results) Messages() []Result {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Result{}, p.results...)
+}
+
+// Errors returns collected errors
+func (p *Results) Errors() []error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]error{}, p.errors...)
+}
+
+// ModuleCalls returns module calls collected during processing
+func (p *Results) ModuleCalls() []ModuleCall {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]ModuleCall{}, p.calls...)
+}
+
+// Changes returns module source changes made during processing
+func (p *Results) Changes() []Change {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Change{}, p.changes...)
+}
+
+// Skips returns module calls which were not changed due to inline directives or other restrictions
+func (p *Results) Skips() []Skip {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Skip{}, p.skips...)
+}
+
+// Written returns files written during processing
+func (p *Results) Written() []Written {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Written{}, p.written...)
+}
+
 // LevelFromString converts string representation of log level to its typed version
 func LevelFromString(logLevel string) (logging.Level, error) {
 	level, ok := map[string]logging.Level{
@@ -2,6 +2,8 @@ package processing
 
 import (
 	"errors"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/maxim-nazarenko/tf-module-update/internal/processing/logging"
@@ -39,6 +41,19 @@ error 2`,
 			items:    []interface{}{errors.New("error 1"), Result{Message: "message 1", Level: logging.INFO}, errors.New("error 2"), Result{Message: "message 2", Level: logging.WARN}},
 			loglevel: logging.WARN,
 		},
+		{
+			name: "nested results keep their messages and errors",
+			expectedResult: `message 1
+message 2
+error 1
+error 2`,
+			items: []interface{}{
+				errors.New("error 1"),
+				Result{Message: "message 1", Level: logging.INFO},
+				&Results{results: []Result{{Message: "message 2", Level: logging.INFO}}, errors: []error{errors.New("error 2")}},
+			},
+			loglevel: logging.INFO,
+		},
 	}
 	assert := testhelpers.Assert(t)
 	for _, tc := range testCases {
@@ -71,6 +86,16 @@ func TestResultsHasErrors(t *testing.T) {
 			expectedResult: true,
 			items:          []interface{}{errors.New("regular error")},
 		},
+		{
+			name:           "error of nested results",
+			expectedResult: true,
+			items:          []interface{}{&Results{errors: []error{errors.New("nested error")}}},
+		},
+		{
+			name:           "nested results without errors",
+			expectedResult: false,
+			items:          []interface{}{&Results{results: []Result{{Message: "a regular message"}}}},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -119,3 +144,23 @@ func TestResultsLevelFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestResultsConcurrentAppend(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	results := NewResults(logging.INFO)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nested := &Results{}
+			nested.Append(Result{Message: "message", Level: logging.INFO})
+			results.Append(nested, errors.New("error"))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(100, len(strings.Split(results.String(), "\n")))
+	assert.Equal(true, results.HasErrors())
+}
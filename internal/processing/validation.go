@@ -0,0 +1,11 @@
+package processing
+
+import "github.com/maxim-nazarenko/tf-module-update/module"
+
+// Validator checks that the new module source exists before it is written,
+// e.g. that its ref and submodule are present in the repository
+//
+// Validate might be called concurrently when files are processed by several jobs
+type Validator interface {
+	Validate(module.Source) error
+}
@@ -0,0 +1,226 @@
+package summary
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/inventory"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+// Markdown renders the run as Markdown suitable for pull request description
+func (r Run) Markdown() string {
+	b := &strings.Builder{}
+
+	updates := r.moduleUpdates()
+	if r.Proposed {
+		b.WriteString("## Proposed module updates\n\n")
+	} else {
+		b.WriteString("## Module updates\n\n")
+	}
+	if len(updates) == 0 && r.Proposed {
+		b.WriteString("No module source updates were proposed.\n")
+	} else if len(updates) == 0 {
+		b.WriteString("No module sources were updated.\n")
+	} else {
+		b.WriteString("| Module | Old versions | New version |\n")
+		b.WriteString("|--------|--------------|-------------|\n")
+		for _, u := range updates {
+			fmt.Fprintf(b, "| %s | %s | %s |\n", escape(u.module), escape(strings.Join(u.oldRevisions, ", ")), escape(u.newRevision))
+		}
+
+		b.WriteString("\n### Affected files\n")
+		dirs, files := r.filesByDir()
+		for _, dir := range dirs {
+			fmt.Fprintf(b, "\n**%s**\n\n", dir)
+			for _, f := range files[dir] {
+				fmt.Fprintf(b, "- %s\n", f)
+			}
+		}
+	}
+
+	if changelogs := r.changelogs(); len(changelogs) > 0 {
+		b.WriteString("\n### Changelog\n")
+		for _, c := range changelogs {
+			fmt.Fprintf(b, "\n#### %s %s → %s\n\n", c.module, c.oldRevision, c.newRevision)
+			for _, subject := range c.changelog.Commits {
+				fmt.Fprintf(b, "- %s\n", subject)
+			}
+			if len(c.changelog.Notes) > 0 {
+				if len(c.changelog.Commits) > 0 {
+					b.WriteString("\n")
+				}
+				b.WriteString("<details>\n<summary>CHANGELOG.md</summary>\n\n")
+				b.WriteString(strings.Join(c.changelog.Notes, "\n\n"))
+				b.WriteString("\n\n</details>\n")
+			}
+		}
+	}
+
+	if changes := r.interfaceChanges(); len(changes) > 0 {
+		b.WriteString("\n### Interface changes\n")
+		for _, c := range changes {
+			fmt.Fprintf(b, "\n**%s**, module `%s`, %s → %s\n\n", r.relativePath(c.Call.File), c.Call.Name, c.Before.RevisionOrString(), c.After.RevisionOrString())
+			for _, f := range c.Interface {
+				if f.Breaking {
+					fmt.Fprintf(b, "- **Breaking:** %s\n", f.Message)
+				} else {
+					fmt.Fprintf(b, "- %s\n", f.Message)
+				}
+			}
+		}
+	}
+
+	if len(r.Skips) > 0 || len(r.Errors) > 0 {
+		b.WriteString("\n### Not updated\n\n")
+		fmt.Fprintf(b, "- Skipped: %d\n", len(r.Skips))
+		fmt.Fprintf(b, "- Errors: %d\n", len(r.Errors))
+	}
+
+	return b.String()
+}
+
+// moduleUpdates groups changes by module and new revision, old revisions are sorted from the oldest
+func (r Run) moduleUpdates() []moduleUpdate {
+	type key struct {
+		module      string
+		newRevision string
+	}
+
+	keys := []key{}
+	old := map[key][]module.Source{}
+	for _, c := range r.Changes {
+		k := key{module: inventory.GroupKey(c.After), newRevision: c.After.RevisionOrString()}
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+		old[k] = append(old[k], c.Before)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].module != keys[j].module {
+			return keys[i].module < keys[j].module
+		}
+		return keys[i].newRevision < keys[j].newRevision
+	})
+
+	updates := make([]moduleUpdate, 0, len(keys))
+	for _, k := range keys {
+		sources := old[k]
+		sort.SliceStable(sources, func(i, j int) bool {
+			return module.CompareRevisions(sources[i].Revision, sources[j].Revision) < 0
+		})
+
+		revisions := []string{}
+		seen := map[string]bool{}
+		for _, s := range sources {
+			revision := s.RevisionOrString()
+			if !seen[revision] {
+				seen[revision] = true
+				revisions = append(revisions, revision)
+			}
+		}
+		updates = append(updates, moduleUpdate{module: k.module, oldRevisions: revisions, newRevision: k.newRevision})
+	}
+
+	return updates
+}
+
+// changelogs returns collected changelogs, one per module and pair of revisions, sorted by module and old revision
+func (r Run) changelogs() []moduleChangelog {
+	changelogs := []moduleChangelog{}
+	seen := map[[3]string]bool{}
+	for _, c := range r.Changes {
+		if c.Changelog.IsEmpty() {
+			continue
+		}
+
+		entry := moduleChangelog{
+			module:      inventory.GroupKey(c.After),
+			oldRevision: c.Before.RevisionOrString(),
+			newRevision: c.After.RevisionOrString(),
+			changelog:   c.Changelog,
+		}
+		key := [3]string{entry.module, entry.oldRevision, entry.newRevision}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		changelogs = append(changelogs, entry)
+	}
+
+	sort.SliceStable(changelogs, func(i, j int) bool {
+		if changelogs[i].module != changelogs[j].module {
+			return changelogs[i].module < changelogs[j].module
+		}
+		return module.CompareRevisions(module.Revision(changelogs[i].oldRevision), module.Revision(changelogs[j].oldRevision)) < 0
+	})
+
+	return changelogs
+}
+
+// interfaceChanges returns changes with interface findings sorted by file and module name
+func (r Run) interfaceChanges() []updater.Change {
+	changes := []updater.Change{}
+	for _, c := range r.Changes {
+		if len(c.Interface) > 0 {
+			changes = append(changes, c)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Call.File != changes[j].Call.File {
+			return changes[i].Call.File < changes[j].Call.File
+		}
+		return changes[i].Call.Name < changes[j].Call.Name
+	})
+
+	return changes
+}
+
+// filesByDir returns sorted folders and names of changed files in each of them
+func (r Run) filesByDir() ([]string, map[string][]string) {
+	dirs := []string{}
+	files := map[string][]string{}
+	seen := map[string]bool{}
+	for _, c := range r.Changes {
+		if seen[c.Call.File] {
+			continue
+		}
+		seen[c.Call.File] = true
+		path := r.relativePath(c.Call.File)
+
+		dir, name := filepath.ToSlash(filepath.Dir(path)), filepath.Base(path)
+		if _, ok := files[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		files[dir] = append(files[dir], name)
+	}
+
+	sort.Strings(dirs)
+	for _, d := range dirs {
+		sort.Strings(files[d])
+	}
+
+	return dirs, files
+}
+
+// relativePath returns slash separated path relative to BaseDir, files outside of it are shown as is
+func (r Run) relativePath(path string) string {
+	if r.BaseDir != "" {
+		if rel, err := filepath.Rel(r.BaseDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+
+	return filepath.ToSlash(path)
+}
+
+// escape makes the text safe to put into Markdown table cell
+func escape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,236 @@
+package summary
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/updater"
+)
+
+func testChange(file, submodule string, before, after module.Revision) updater.Change {
+	source := module.Source{Scheme: "https", Host: "github.com", Module: "/example-org/modules.git", Submodule: submodule}
+	c := updater.Change{Call: updater.ModuleCall{File: file, Block: "module"}, Before: source, After: source}
+	c.Before.Revision = before
+	c.After.Revision = after
+
+	return c
+}
+
+func withChangelog(c updater.Change, changelog updater.Changelog) updater.Change {
+	c.Changelog = changelog
+	return c
+}
+
+func withInterface(c updater.Change, findings []updater.InterfaceFinding) updater.Change {
+	c.Call.Name = "vpc"
+	c.Interface = findings
+	return c
+}
+
+func TestRunMarkdown(t *testing.T) {
+	testCases := []struct {
+		name           string
+		run            Run
+		expectedResult string
+	}{
+		{
+			name: "updates",
+			run: Run{
+				Changes: []updater.Change{
+					testChange("/repo/envs/prod/main.tf", "//vpc", "v1.10.0", "v2.0.0"),
+					testChange("/repo/envs/prod/db.tf", "//db", "v1.0.0", "v1.1.0"),
+					testChange("/repo/envs/stage/main.tf", "//vpc", "v1.9.0", "v2.0.0"),
+					testChange("/repo/envs/stage/main.tf", "//vpc", "v1.10.0", "v2.0.0"),
+					testChange("/elsewhere/main.tf", "//vpc", "main", "v2.0.0"),
+				},
+				Skips:   []updater.Skip{{Reason: "ignore directive"}},
+				BaseDir: "/repo",
+			},
+			expectedResult: `## Module updates
+
+| Module | Old versions | New version |
+|--------|--------------|-------------|
+| github.com/example-org/modules//db | v1.0.0 | v1.1.0 |
+| github.com/example-org/modules//vpc | main, v1.9.0, v1.10.0 | v2.0.0 |
+
+### Affected files
+
+**/elsewhere**
+
+- main.tf
+
+**envs/prod**
+
+- db.tf
+- main.tf
+
+**envs/stage**
+
+- main.tf
+
+### Not updated
+
+- Skipped: 1
+- Errors: 0
+`,
+		},
+		{
+			name: "changelogs",
+			run: Run{
+				Changes: []updater.Change{
+					withChangelog(testChange("/repo/prod/main.tf", "//vpc", "v1.4.0", "v1.5.0"), updater.Changelog{
+						Commits: []string{"Add IPv6 support"},
+					}),
+					withChangelog(testChange("/repo/stage/main.tf", "//vpc", "v1.2.0", "v1.5.0"), updater.Changelog{
+						Commits: []string{"Add IPv6 support", "Fix NAT gateway tags"},
+						Notes:   []string{"## v1.5.0\n\n- IPv6", "## v1.4.0\n\n- Tags"},
+					}),
+					// the same pair of revisions is rendered once
+					withChangelog(testChange("/repo/dev/main.tf", "//vpc", "v1.2.0", "v1.5.0"), updater.Changelog{
+						Commits: []string{"Add IPv6 support", "Fix NAT gateway tags"},
+					}),
+					testChange("/repo/prod/db.tf", "//db", "v1.0.0", "v1.1.0"),
+				},
+				BaseDir: "/repo",
+			},
+			expectedResult: `## Module updates
+
+| Module | Old versions | New version |
+|--------|--------------|-------------|
+| github.com/example-org/modules//db | v1.0.0 | v1.1.0 |
+| github.com/example-org/modules//vpc | v1.2.0, v1.4.0 | v1.5.0 |
+
+### Affected files
+
+**dev**
+
+- main.tf
+
+**prod**
+
+- db.tf
+- main.tf
+
+**stage**
+
+- main.tf
+
+### Changelog
+
+#### github.com/example-org/modules//vpc v1.2.0 → v1.5.0
+
+- Add IPv6 support
+- Fix NAT gateway tags
+
+<details>
+<summary>CHANGELOG.md</summary>
+
+## v1.5.0
+
+- IPv6
+
+## v1.4.0
+
+- Tags
+
+</details>
+
+#### github.com/example-org/modules//vpc v1.4.0 → v1.5.0
+
+- Add IPv6 support
+`,
+		},
+		{
+			name: "interface changes",
+			run: Run{
+				Changes: []updater.Change{
+					withInterface(testChange("/repo/stage/main.tf", "//vpc", "v1.0.0", "v2.0.0"), []updater.InterfaceFinding{
+						{Kind: "removed-output", Name: "id", Message: `output "id" is removed`},
+					}),
+					withInterface(testChange("/repo/prod/main.tf", "//vpc", "v1.0.0", "v2.0.0"), []updater.InterfaceFinding{
+						{Kind: "required-variable", Name: "region", Breaking: true, Message: `required variable "region" is added, the call does not set it`},
+						{Kind: "removed-output", Name: "id", Message: `output "id" is removed`},
+					}),
+				},
+				BaseDir: "/repo",
+			},
+			expectedResult: `## Module updates
+
+| Module | Old versions | New version |
+|--------|--------------|-------------|
+| github.com/example-org/modules//vpc | v1.0.0 | v2.0.0 |
+
+### Affected files
+
+**prod**
+
+- main.tf
+
+**stage**
+
+- main.tf
+
+### Interface changes
+
+**prod/main.tf**, module ` + "`vpc`" + `, v1.0.0 → v2.0.0
+
+- **Breaking:** required variable "region" is added, the call does not set it
+- output "id" is removed
+
+**stage/main.tf**, module ` + "`vpc`" + `, v1.0.0 → v2.0.0
+
+- output "id" is removed
+`,
+		},
+		{
+			name: "nothing updated",
+			run:  Run{Errors: []error{errors.New("broken")}},
+			expectedResult: `## Module updates
+
+No module sources were updated.
+
+### Not updated
+
+- Skipped: 0
+- Errors: 1
+`,
+		},
+		{
+			name: "proposed updates",
+			run: Run{
+				Changes:  []updater.Change{testChange("/repo/main.tf", "//db", "v1.0.0", "v1.1.0")},
+				BaseDir:  "/repo",
+				Proposed: true,
+			},
+			expectedResult: `## Proposed module updates
+
+| Module | Old versions | New version |
+|--------|--------------|-------------|
+| github.com/example-org/modules//db | v1.0.0 | v1.1.0 |
+
+### Affected files
+
+**.**
+
+- main.tf
+`,
+		},
+		{
+			name: "nothing proposed",
+			run:  Run{Proposed: true},
+			expectedResult: `## Proposed module updates
+
+No module source updates were proposed.
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			assert.Equal(tc.expectedResult, tc.run.Markdown())
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package summary
+
+import "github.com/maxim-nazarenko/tf-module-update/updater"
+
+// Run holds results of a single run to summarize
+type Run struct {
+	Changes []updater.Change
+	Skips   []updater.Skip
+	Errors  []error
+
+	// BaseDir is the folder file paths are shown relative to, paths are shown as is when it is empty
+	BaseDir string
+
+	// Proposed reports that files were not written, so changes are shown as proposed ones
+	Proposed bool
+}
+
+// moduleUpdate is a row of updates table
+type moduleUpdate struct {
+	module       string
+	oldRevisions []string
+	newRevision  string
+}
+
+// moduleChangelog is the changelog of a module between a pair of revisions
+type moduleChangelog struct {
+	module      string
+	oldRevision string
+	newRevision string
+	changelog   updater.Changelog
+}
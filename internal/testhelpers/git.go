@@ -0,0 +1,52 @@
+package testhelpers
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// NewGitRepository creates a throwaway repository with the files committed,
+// files are given as slash-separated name and content pairs. The test is skipped if git is not installed
+func NewGitRepository(t *testing.T, files ...string) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	RunGit(t, dir, "init", "-q")
+	RunGit(t, dir, "config", "user.name", "Test")
+	RunGit(t, dir, "config", "user.email", "test@example.com")
+	RunGit(t, dir, "config", "commit.gpgsign", "false")
+	RunGit(t, dir, "config", "tag.gpgsign", "false")
+	CommitFiles(t, dir, "initial", files...)
+
+	return dir
+}
+
+// CommitFiles writes files given as slash-separated name and content pairs and commits all changes of the repository
+func CommitFiles(t *testing.T, dir string, subject string, files ...string) {
+	for i := 0; i+1 < len(files); i += 2 {
+		name := filepath.Join(dir, filepath.FromSlash(files[i]))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(files[i+1]), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	RunGit(t, dir, "add", ".")
+	RunGit(t, dir, "commit", "-q", "--allow-empty", "-m", subject)
+}
+
+// RunGit runs git command in the folder and returns its output without trailing new line
+func RunGit(t *testing.T, dir string, args ...string) string {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %s", strings.Join(args, " "), out)
+	}
+
+	return strings.TrimRight(string(out), "\n")
+}
@@ -0,0 +1,107 @@
+package module
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a set of version requirements in Terraform syntax, e.g. ">= 1.2.0, < 2.0.0" or "~> 2.1"
+//
+// A revision satisfies the constraint if it satisfies all requirements
+type Constraint struct {
+	text         string
+	requirements []requirement
+}
+
+type requirement struct {
+	operator string
+	version  Version
+	// segments is the number of version parts written explicitly, used by "~>" operator
+	segments int
+}
+
+// constraintOperators are ordered so two-character operators are matched first
+var constraintOperators = []string{">=", "<=", "!=", "~>", "=", ">", "<"}
+
+// ParseConstraint parses comma separated list of requirements
+func ParseConstraint(text string) (Constraint, error) {
+	c := Constraint{text: text}
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return c, fmt.Errorf("empty requirement in constraint %q", text)
+		}
+
+		operator := "="
+		for _, op := range constraintOperators {
+			if strings.HasPrefix(part, op) {
+				operator = op
+				part = strings.TrimSpace(part[len(op):])
+				break
+			}
+		}
+
+		version, ok := Revision(part).Version()
+		if !ok {
+			return c, fmt.Errorf("invalid version %q in constraint %q", part, text)
+		}
+
+		segments := len(strings.Split(strings.SplitN(strings.TrimPrefix(part, "v"), "-", 2)[0], "."))
+		c.requirements = append(c.requirements, requirement{operator: operator, version: version, segments: segments})
+	}
+
+	return c, nil
+}
+
+// Allows reports if the revision satisfies the constraint, revisions which are not versions are never allowed
+func (c Constraint) Allows(r Revision) bool {
+	version, ok := r.Version()
+	if !ok {
+		return false
+	}
+
+	for _, req := range c.requirements {
+		if !req.allows(version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Constraint) String() string {
+	return c.text
+}
+
+func (r requirement) allows(v Version) bool {
+	cmp := v.Compare(r.version)
+	switch r.operator {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "~>":
+		return cmp >= 0 && v.Compare(r.pessimisticUpperBound()) < 0
+	}
+
+	return false
+}
+
+// pessimisticUpperBound returns the first version not allowed by "~>" operator:
+// only the rightmost written part may increase, e.g. "~> 2.1" allows < 3.0.0 and "~> 2.1.0" allows < 2.2.0
+func (r requirement) pessimisticUpperBound() Version {
+	switch {
+	case r.segments >= 3:
+		return Version{Major: r.version.Major, Minor: r.version.Minor + 1}
+	default:
+		return Version{Major: r.version.Major + 1}
+	}
+}
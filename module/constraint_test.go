@@ -0,0 +1,52 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestConstraintAllows(t *testing.T) {
+	testCases := []struct {
+		constraint     string
+		revision       Revision
+		expectedResult bool
+	}{
+		{constraint: "1.2.0", revision: "v1.2.0", expectedResult: true},
+		{constraint: "= v1.2.0", revision: "v1.2.1", expectedResult: false},
+		{constraint: "!= 1.2.0", revision: "v1.2.1", expectedResult: true},
+		{constraint: ">= 1.2.0, < 2.0.0", revision: "v1.9.9", expectedResult: true},
+		{constraint: ">= 1.2.0, < 2.0.0", revision: "v2.0.0", expectedResult: false},
+		{constraint: "> 1.2.0", revision: "v1.2.0", expectedResult: false},
+		{constraint: "<= 1.2.0", revision: "v1.2.0", expectedResult: true},
+		{constraint: "~> 2.1", revision: "v2.9.0", expectedResult: true},
+		{constraint: "~> 2.1", revision: "v3.0.0", expectedResult: false},
+		{constraint: "~> 2.1", revision: "v2.0.9", expectedResult: false},
+		{constraint: "~> 2.1.0", revision: "v2.1.7", expectedResult: true},
+		{constraint: "~> 2.1.0", revision: "v2.2.0", expectedResult: false},
+		{constraint: "~> 2", revision: "v2.5.0", expectedResult: true},
+		{constraint: "~> 2", revision: "v3.0.0", expectedResult: false},
+		{constraint: ">= 1.0.0", revision: "main", expectedResult: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint+" "+string(tc.revision), func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			constraint, err := ParseConstraint(tc.constraint)
+			assert.NoError(err)
+			assert.Equal(tc.expectedResult, constraint.Allows(tc.revision))
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, text := range []string{"", ">= 1.0,", "~> main", ">=> 1.0"} {
+		t.Run(text, func(t *testing.T) {
+			_, err := ParseConstraint(text)
+			if err == nil {
+				t.Fatalf("expected error for constraint %q", text)
+			}
+		})
+	}
+}
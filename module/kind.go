@@ -0,0 +1,61 @@
+package module
+
+import "strings"
+
+// Kind describes where the module is loaded from
+type Kind string
+
+const (
+	KindGit      Kind = "git"
+	KindRegistry Kind = "registry"
+	KindLocal    Kind = "local"
+	KindOther    Kind = "other"
+)
+
+// wellKnownGitHosts are hosts which Terraform treats as git repositories without explicit "git::" prefix
+var wellKnownGitHosts = []string{"github.com", "bitbucket.org"}
+
+// Kind detects kind of the module source
+func (s Source) Kind() Kind {
+	if s.Scheme == "" && s.Host == "" && s.SpecialPrefix == "" &&
+		(strings.HasPrefix(s.Module, "./") || strings.HasPrefix(s.Module, "../")) {
+		return KindLocal
+	}
+
+	if s.Scheme == RegistryScheme {
+		return KindRegistry
+	}
+
+	if s.SpecialPrefix == "git::" || s.Scheme == "ssh" || s.Scheme == "git" || strings.HasSuffix(s.Module, ".git") {
+		return KindGit
+	}
+
+	for _, h := range wellKnownGitHosts {
+		if strings.EqualFold(s.Host, h) {
+			return KindGit
+		}
+	}
+
+	// registry address is <namespace>/<name>/<provider> with optional hostname in front
+	if s.Scheme == "" && s.SpecialPrefix == "" {
+		parts := strings.Split(s.Module, "/")
+		if len(parts) == 3 || (len(parts) == 4 && strings.Contains(parts[0], ".")) {
+			return KindRegistry
+		}
+	}
+
+	return KindOther
+}
+
+// Repository returns canonical address of repository the module belongs to
+//
+// Scheme, user, special prefix and ".git" suffix are dropped and host is lowercased,
+// so different spellings of the same repository result in the same string
+func (s Source) Repository() string {
+	module := strings.TrimSuffix(s.Module, ".git")
+	if s.Host == "" {
+		return strings.TrimPrefix(module, "/")
+	}
+
+	return strings.ToLower(s.Host) + module
+}
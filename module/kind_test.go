@@ -0,0 +1,58 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestKind(t *testing.T) {
+	testCases := []struct {
+		sourceString   string
+		expectedResult Kind
+	}{
+		{sourceString: "git::https://example.com/example-org/aws/vpc.git?ref=0.0.1", expectedResult: KindGit},
+		{sourceString: "github.com/example-org/aws-vpc?ref=0.0.1", expectedResult: KindGit},
+		{sourceString: "git::ssh://git@example.com/example-org/aws-vpc?ref=0.0.1", expectedResult: KindGit},
+		{sourceString: "https://example.com/example-org/aws/vpc.git", expectedResult: KindGit},
+		{sourceString: "terraform-aws-modules/vpc/aws", expectedResult: KindRegistry},
+		{sourceString: "app.terraform.io/example-org/vpc/aws", expectedResult: KindRegistry},
+		{sourceString: "tfr:///terraform-aws-modules/vpc/aws?version=3.3.0", expectedResult: KindRegistry},
+		{sourceString: "./modules/vpc", expectedResult: KindLocal},
+		{sourceString: "../vpc", expectedResult: KindLocal},
+		{sourceString: "https://example.com/vpc-module.zip", expectedResult: KindOther},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sourceString, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			source, err := ParseSource(tc.sourceString)
+			assert.NoError(err)
+			assert.Equal(tc.expectedResult, source.Kind())
+		})
+	}
+}
+
+func TestRepository(t *testing.T) {
+	testCases := []struct {
+		sourceString   string
+		expectedResult string
+	}{
+		{sourceString: "git::https://GitHub.com/example-org/modules.git//vpc?ref=0.0.1", expectedResult: "github.com/example-org/modules"},
+		{sourceString: "github.com/example-org/modules//vpc?ref=0.0.2", expectedResult: "github.com/example-org/modules"},
+		{sourceString: "git::ssh://git@github.com/example-org/modules.git", expectedResult: "github.com/example-org/modules"},
+		{sourceString: "tfr:///terraform-aws-modules/vpc/aws?version=3.3.0", expectedResult: "terraform-aws-modules/vpc/aws"},
+		{sourceString: "terraform-aws-modules/vpc/aws", expectedResult: "terraform-aws-modules/vpc/aws"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sourceString, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			source, err := ParseSource(tc.sourceString)
+			assert.NoError(err)
+			assert.Equal(tc.expectedResult, source.Repository())
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package module
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NormalizePolicy describes canonical form of git module sources
+type NormalizePolicy struct {
+	// GitPrefix requires "git::" prefix for all git sources
+	GitPrefix bool
+
+	// Scheme is the scheme git sources are converted to, "https" or "ssh", empty keeps the original one
+	Scheme string
+
+	// GitSuffix requires repository path to end with ".git"
+	GitSuffix bool
+}
+
+// Well-known normalization policies
+var NormalizePolicies = map[string]NormalizePolicy{
+	// git::https://host/org/repo.git//path?ref=...
+	"git-https": {GitPrefix: true, Scheme: "https", GitSuffix: true},
+	// git::ssh://git@host/org/repo.git//path?ref=...
+	"git-ssh": {GitPrefix: true, Scheme: "ssh", GitSuffix: true},
+	// only cleans up host and submodule
+	"minimal": {},
+}
+
+var (
+	repeatedSlashes = regexp.MustCompile(`/{2,}`)
+	gitSuffixes     = regexp.MustCompile(`(?i)(\.git)+/*$`)
+)
+
+// Normalize rewrites source into canonical form defined by the policy
+//
+// Unlike String(), which reproduces the source as it was written, Normalize makes
+// different spellings of the same git source identical. Host is always lowercased and submodule
+// is cleaned from repeated and trailing slashes. Host shorthand like "github.com/org/repo" is expanded
+// to a URL only by policies with GitPrefix, so the source is still cloned with git. Sources other than
+// git ones are returned as is
+func Normalize(s Source, policy NormalizePolicy) Source {
+	if s.Kind() != KindGit {
+		return s
+	}
+
+	// "example.com/org/repo.git" has no scheme, so the host is parsed as a part of the module path.
+	// Such shorthand is expanded only together with "git::" prefix, as Terraform downloads
+	// "https://..." sources without the prefix over HTTP instead of cloning them
+	if policy.GitPrefix && s.Scheme == "" && s.Host == "" {
+		parts := strings.SplitN(strings.TrimPrefix(s.Module, "/"), "/", 2)
+		if len(parts) == 2 && strings.Contains(parts[0], ".") {
+			s.Scheme = "https"
+			s.Host = parts[0]
+			s.Module = "/" + parts[1]
+		}
+	}
+
+	s.Host = strings.ToLower(s.Host)
+
+	if s.Submodule != "" {
+		s.Submodule = "//" + strings.Trim(repeatedSlashes.ReplaceAllString(s.Submodule, "/"), "/")
+		if s.Submodule == "//" {
+			s.Submodule = ""
+		}
+	}
+
+	if policy.GitSuffix {
+		s.Module = gitSuffixes.ReplaceAllString(strings.TrimRight(s.Module, "/"), "") + ".git"
+	}
+
+	switch policy.Scheme {
+	case "https":
+		if s.Scheme == "http" || s.Scheme == "ssh" || s.Scheme == "git" {
+			s.Scheme = "https"
+			s.User = ""
+		}
+	case "ssh":
+		if s.Scheme == "http" || s.Scheme == "https" || s.Scheme == "git" {
+			s.Scheme = "ssh"
+			s.User = "git"
+		}
+	}
+
+	// ParseSource expands "github.com/org/repo" shorthand to https URL, which Terraform downloads
+	// over HTTP instead of cloning without "git::" prefix, so the shorthand is written back then
+	if !policy.GitPrefix && s.SpecialPrefix == "" && s.Scheme == "https" && s.User == "" && s.Host == "github.com" {
+		s.Scheme = ""
+	}
+
+	if policy.GitPrefix {
+		s.SpecialPrefix = "git::"
+	}
+
+	return s
+}
+
+// PolicyFromString returns well-known policy by its name
+func PolicyFromString(name string) (NormalizePolicy, error) {
+	policy, ok := NormalizePolicies[name]
+	if !ok {
+		return policy, fmt.Errorf("unknown normalization policy: %s", name)
+	}
+
+	return policy, nil
+}
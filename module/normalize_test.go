@@ -0,0 +1,90 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name           string
+		policy         string
+		sourceString   string
+		expectedResult string
+	}{
+		{
+			name:           "canonical source is not changed",
+			policy:         "git-https",
+			sourceString:   "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0",
+			expectedResult: "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0",
+		},
+		{
+			name:           "github shorthand",
+			policy:         "git-https",
+			sourceString:   "github.com/example-org/modules//vpc/?ref=v1.0.0",
+			expectedResult: "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0",
+		},
+		{
+			name:           "host without scheme",
+			policy:         "git-https",
+			sourceString:   "Example.com/example-org/modules.git//src//vpc?ref=v1.0.0",
+			expectedResult: "git::https://example.com/example-org/modules.git//src/vpc?ref=v1.0.0",
+		},
+		{
+			name:           "ssh to https",
+			policy:         "git-https",
+			sourceString:   "git::ssh://git@github.com/example-org/modules.GIT?ref=v1.0.0",
+			expectedResult: "git::https://github.com/example-org/modules.git?ref=v1.0.0",
+		},
+		{
+			name:           "https to ssh",
+			policy:         "git-ssh",
+			sourceString:   "https://github.com/example-org/modules?ref=v1.0.0",
+			expectedResult: "git::ssh://git@github.com/example-org/modules.git?ref=v1.0.0",
+		},
+		{
+			name:           "minimal keeps scheme and prefix",
+			policy:         "minimal",
+			sourceString:   "https://GitLab.com/example-org/modules.git//vpc//?ref=v1.0.0",
+			expectedResult: "https://gitlab.com/example-org/modules.git//vpc?ref=v1.0.0",
+		},
+		{
+			name:           "minimal keeps host shorthand",
+			policy:         "minimal",
+			sourceString:   "github.com/example-org/modules//vpc/?ref=v1.0.0",
+			expectedResult: "github.com/example-org/modules//vpc?ref=v1.0.0",
+		},
+		{
+			name:           "minimal keeps host shorthand without scheme",
+			policy:         "minimal",
+			sourceString:   "Example.com/example-org/modules.git//vpc?ref=v1.0.0",
+			expectedResult: "Example.com/example-org/modules.git//vpc?ref=v1.0.0",
+		},
+		{
+			name:           "registry source is not changed",
+			policy:         "git-https",
+			sourceString:   "terraform-aws-modules/vpc/aws",
+			expectedResult: "terraform-aws-modules/vpc/aws",
+		},
+		{
+			name:           "local source is not changed",
+			policy:         "git-https",
+			sourceString:   "./modules//vpc",
+			expectedResult: "./modules//vpc",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			policy, err := PolicyFromString(tc.policy)
+			assert.NoError(err)
+			source, err := ParseSource(tc.sourceString)
+			assert.NoError(err)
+
+			assert.Equal(tc.expectedResult, Normalize(source, policy).String())
+		})
+	}
+}
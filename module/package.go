@@ -3,6 +3,7 @@ package module
 // Source describes module source with possible submodule and revision
 type Source struct {
 	Scheme        string
+	User          string // user info, e.g. "git" for ssh://git@github.com sources
 	Host          string
 	SpecialPrefix string
 	Module        string // module name, including organization name for github
@@ -12,3 +13,6 @@ type Source struct {
 
 // Revision represents revision of a module
 type Revision string
+
+// RegistryScheme is the scheme of Terraform registry sources used by Terragrunt, e.g. tfr:///org/name/provider
+const RegistryScheme = "tfr"
@@ -0,0 +1,132 @@
+package module
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Version is semantic version parsed from a revision like "v1.2.3" or "1.2.3-rc.1"
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// Version parses revision as semantic version
+//
+// Leading "v" is optional, as well as minor and patch parts, e.g. "v2" is the same as "2.0.0".
+// Build metadata after "+" is ignored. The second value is false if revision is not a version
+func (r Revision) Version() (Version, bool) {
+	s := strings.TrimPrefix(string(r), "v")
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+
+	v := Version{}
+	if i := strings.Index(s, "-"); i >= 0 {
+		v.Prerelease = s[i+1:]
+		s = s[:i]
+		if v.Prerelease == "" {
+			return Version{}, false
+		}
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return Version{}, false
+	}
+
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, false
+		}
+		*numbers[i] = n
+	}
+
+	return v, true
+}
+
+// Compare returns -1, 0 or 1 if the version is lower, equal or greater than the other one
+//
+// Prerelease versions are lower than the release with the same numbers
+func (v Version) Compare(o Version) int {
+	for _, pair := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if pair[0] != pair[1] {
+			return compareInts(pair[0], pair[1])
+		}
+	}
+
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// CompareRevisions orders revisions, so sorting by it puts the newest one last
+//
+// Revisions which are not versions, e.g. branch names or commit hashes,
+// are considered lower than any version and compared as strings between each other
+func CompareRevisions(a, b Revision) int {
+	av, aok := a.Version()
+	bv, bok := b.Version()
+	switch {
+	case aok && bok:
+		if c := av.Compare(bv); c != 0 {
+			return c
+		}
+		// "v1.0.0" and "1.0.0" are equal versions, but still have to be ordered in a stable way
+		return strings.Compare(string(a), string(b))
+	case aok:
+		return 1
+	case bok:
+		return -1
+	}
+
+	return strings.Compare(string(a), string(b))
+}
+
+// comparePrerelease compares dot separated prerelease identifiers, numeric ones are compared as numbers
+func comparePrerelease(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		an, aErr := strconv.Atoi(aParts[i])
+		bn, bErr := strconv.Atoi(bParts[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return compareInts(an, bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return compareInts(len(aParts), len(bParts))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,59 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+)
+
+func TestRevisionVersion(t *testing.T) {
+	testCases := []struct {
+		revision       Revision
+		expectedResult Version
+		expectedOk     bool
+	}{
+		{revision: "v1.2.3", expectedResult: Version{Major: 1, Minor: 2, Patch: 3}, expectedOk: true},
+		{revision: "1.2.3", expectedResult: Version{Major: 1, Minor: 2, Patch: 3}, expectedOk: true},
+		{revision: "v2", expectedResult: Version{Major: 2}, expectedOk: true},
+		{revision: "v1.0.0-rc.1+build.5", expectedResult: Version{Major: 1, Prerelease: "rc.1"}, expectedOk: true},
+		{revision: "main", expectedOk: false},
+		{revision: "v1.2.3.4", expectedOk: false},
+		{revision: "v1.2.x", expectedOk: false},
+		{revision: "", expectedOk: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.revision), func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+
+			version, ok := tc.revision.Version()
+			assert.Equal(tc.expectedOk, ok)
+			assert.Equal(tc.expectedResult, version)
+		})
+	}
+}
+
+func TestCompareRevisions(t *testing.T) {
+	testCases := []struct {
+		a              Revision
+		b              Revision
+		expectedResult int
+	}{
+		{a: "v1.2.3", b: "v1.2.3", expectedResult: 0},
+		{a: "v1.2.3", b: "v1.10.0", expectedResult: -1},
+		{a: "v2.0.0", b: "v1.10.0", expectedResult: 1},
+		{a: "v1.0.0-rc.1", b: "v1.0.0", expectedResult: -1},
+		{a: "v1.0.0-rc.2", b: "v1.0.0-rc.10", expectedResult: -1},
+		{a: "v1.0.0-alpha", b: "v1.0.0-alpha.1", expectedResult: -1},
+		{a: "main", b: "v0.0.1", expectedResult: -1},
+		{a: "develop", b: "main", expectedResult: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.a)+" vs "+string(tc.b), func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			assert.Equal(tc.expectedResult, CompareRevisions(tc.a, tc.b))
+			assert.Equal(-tc.expectedResult, CompareRevisions(tc.b, tc.a))
+		})
+	}
+}
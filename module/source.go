@@ -18,7 +18,7 @@ import (
 func (s Source) String() string {
 	revision := ""
 	if s.Revision != "" {
-		revision = "?ref=" + string(s.Revision)
+		revision = "?" + revisionParam(s.Scheme) + "=" + string(s.Revision)
 	}
 
 	scheme := ""
@@ -26,7 +26,22 @@ func (s Source) String() string {
 		scheme = s.Scheme + "://"
 	}
 
-	return s.SpecialPrefix + scheme + s.Host + s.Module + s.Submodule + revision
+	user := ""
+	if s.User != "" {
+		user = s.User + "@"
+	}
+
+	return s.SpecialPrefix + scheme + user + s.Host + s.Module + s.Submodule + revision
+}
+
+// RevisionOrString returns the revision, or the whole source when it has no revision,
+// so sources of updates can be shown shortly
+func (s Source) RevisionOrString() string {
+	if s.Revision != "" {
+		return string(s.Revision)
+	}
+
+	return s.String()
 }
 
 // Merge combines two sources and returns new struct
@@ -39,6 +54,10 @@ func (s Source) Merge(o Source) Source {
 		merged.Scheme = o.Scheme
 	}
 
+	if o.User != "" {
+		merged.User = o.User
+	}
+
 	if o.Host != "" {
 		merged.Host = o.Host
 	}
@@ -111,13 +130,20 @@ func ParseSource(source string) (Source, error) {
 		return result, &InvalidSourceFormatError{"more than 1 query parameter found"}
 	}
 
-	ref := query.Get("ref")
+	param := revisionParam(parsedSource.Scheme)
+	ref := query.Get(param)
 	if len(query) == 1 && ref == "" {
-		return result, &InvalidSourceFormatError{"query param is provided but it is not 'ref'"}
+		return result, &InvalidSourceFormatError{fmt.Sprintf("query param is provided but it is not '%s'", param)}
+	}
+
+	user := ""
+	if parsedSource.User != nil {
+		user = parsedSource.User.String()
 	}
 
 	return Source{
 		Scheme:        parsedSource.Scheme,
+		User:          user,
 		Host:          parsedSource.Host,
 		SpecialPrefix: specialPrefix,
 		Module:        modulePath,
@@ -125,3 +151,14 @@ func ParseSource(source string) (Source, error) {
 		Revision:      Revision(ref),
 	}, nil
 }
+
+// revisionParam returns name of query parameter which holds revision for the given scheme
+//
+// Terraform registry sources use "version", all others use git-style "ref"
+func revisionParam(scheme string) string {
+	if scheme == RegistryScheme {
+		return "version"
+	}
+
+	return "ref"
+}
@@ -55,6 +55,18 @@ func TestParse(t *testing.T) {
 			sourceString:   "http://",
 			expectedStruct: Source{Scheme: "http"},
 		},
+		{
+			name:           "terragrunt registry source",
+			expectedError:  nil,
+			sourceString:   "tfr:///terraform-aws-modules/vpc/aws//modules/vpc-endpoints?version=3.3.0",
+			expectedStruct: Source{Scheme: "tfr", Module: "/terraform-aws-modules/vpc/aws", Submodule: "//modules/vpc-endpoints", Revision: Revision("3.3.0")},
+		},
+		{
+			name:           "ssh user is kept",
+			expectedError:  nil,
+			sourceString:   "git::ssh://git@github.com/example-org/aws.git//vpc?ref=v1.0.0",
+			expectedStruct: Source{Scheme: "ssh", User: "git", Host: "github.com", SpecialPrefix: "git::", Module: "/example-org/aws.git", Submodule: "//vpc", Revision: Revision("v1.0.0")},
+		},
 		// negative scenarios
 		{
 			name:           "unsupported prefix",
@@ -62,6 +74,12 @@ func TestParse(t *testing.T) {
 			sourceString:   "hg::example.com/example-org/aws/vpc.git?ref=0.0.1",
 			expectedStruct: Source{},
 		},
+		{
+			name:           "ref is not supported for registry sources",
+			expectedError:  &InvalidSourceFormatError{},
+			sourceString:   "tfr:///terraform-aws-modules/vpc/aws?ref=3.3.0",
+			expectedStruct: Source{},
+		},
 		{
 			name:           "more than one query parameter is unsupported",
 			expectedError:  &InvalidSourceFormatError{},
@@ -106,6 +124,16 @@ func TestString(t *testing.T) {
 			expectedResult: "https://example.com/example-org/aws/vpc.git//src/multizone?ref=0.0.1",
 			sourceStruct:   Source{Scheme: "https", Host: "example.com", Module: "/example-org/aws/vpc.git", Submodule: "//src/multizone", Revision: Revision("0.0.1")},
 		},
+		{
+			name:           "registry source uses version",
+			expectedResult: "tfr:///terraform-aws-modules/vpc/aws?version=3.3.0",
+			sourceStruct:   Source{Scheme: "tfr", Module: "/terraform-aws-modules/vpc/aws", Revision: Revision("3.3.0")},
+		},
+		{
+			name:           "ssh with user",
+			expectedResult: "git::ssh://git@github.com/example-org/aws.git?ref=v1.0.0",
+			sourceStruct:   Source{Scheme: "ssh", User: "git", SpecialPrefix: "git::", Host: "github.com", Module: "/example-org/aws.git", Revision: Revision("v1.0.0")},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -118,6 +146,15 @@ func TestString(t *testing.T) {
 	}
 }
 
+func TestRevisionOrString(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	source := Source{Scheme: "https", Host: "github.com", Module: "/example-org/aws/vpc.git"}
+
+	assert.Equal("https://github.com/example-org/aws/vpc.git", source.RevisionOrString())
+	source.Revision = Revision("v1.0.0")
+	assert.Equal("v1.0.0", source.RevisionOrString())
+}
+
 func TestMerge(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -0,0 +1,159 @@
+package strategies
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+// Latest strategy updates revision of module source to the newest released version
+//
+// Only releases which are versions without prerelease part and are at least minAge old are taken into account,
+// newer releases which are too young are reported as held back. Like Strict, all conditions must be satisfied,
+// sources pinned to revisions which are not versions, e.g. branches, are not updated
+type Latest struct {
+	conditions []conditions.Condition
+	releases   ReleaseLister
+	minAge     time.Duration
+	now        func() time.Time
+}
+
+var _ Explainer = (*Latest)(nil)
+var _ Skipper = (*Latest)(nil)
+
+// WithCondition adds condition to the chain of conditions
+func (l *Latest) WithCondition(cond conditions.Condition) *Latest {
+	l.conditions = append(l.conditions, cond)
+
+	return l
+}
+
+// WithConditions is a convenient way to add multiple conditions at once
+func (l *Latest) WithConditions(conds ...conditions.Condition) *Latest {
+	l.conditions = append(l.conditions, conds...)
+
+	return l
+}
+
+// Decide checks all conditions to make decision if the module source should be updated
+// Returns false if no conditions were applied during checking or the revision is not a version
+func (l *Latest) Decide(source module.Source) bool {
+	if _, ok := source.Revision.Version(); !ok || len(l.conditions) == 0 {
+		return false
+	}
+
+	for _, v := range l.conditions {
+		if !v(source) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply creates clone of module source with the newest eligible release, the source is returned as is
+// if there is no such release or releases cannot be listed, see SkipReason
+func (l *Latest) Apply(source module.Source) module.Source {
+	revision, _, err := l.pick(source)
+	if err != nil {
+		return source
+	}
+
+	source.Revision = revision
+	return source
+}
+
+// Explain reports releases newer than the applied one which are held back
+func (l *Latest) Explain(source module.Source) []string {
+	revision, held, err := l.pick(source)
+	if err != nil || revision == source.Revision || len(held) == 0 {
+		return nil
+	}
+
+	return []string{l.heldBack(held)}
+}
+
+// SkipReason reports newer releases which are held back when the source is left as is,
+// empty if it already has the newest release or is updated. Error is returned if releases cannot be listed
+func (l *Latest) SkipReason(source module.Source) (string, error) {
+	revision, held, err := l.pick(source)
+	if err != nil {
+		return "", err
+	}
+	if revision != source.Revision || len(held) == 0 {
+		return "", nil
+	}
+
+	return l.heldBack(held), nil
+}
+
+// pick returns the newest eligible release, which is the current revision if there are no newer ones,
+// and newer releases held back due to minimal age
+func (l *Latest) pick(source module.Source) (module.Revision, []Release, error) {
+	current, ok := source.Revision.Version()
+	if !ok {
+		return "", nil, fmt.Errorf("revision %q is not a version", source.Revision)
+	}
+
+	releases, err := l.releases.Releases(source)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot list releases: %s", err)
+	}
+
+	cutoff := l.now().Add(-l.minAge)
+	best, bestVersion := source.Revision, current
+	candidates := []Release{}
+	for _, r := range releases {
+		v, ok := r.Revision.Version()
+		if !ok || v.Prerelease != "" || v.Compare(current) <= 0 {
+			continue
+		}
+		if r.Date.After(cutoff) {
+			candidates = append(candidates, r)
+			continue
+		}
+		if v.Compare(bestVersion) > 0 {
+			best, bestVersion = r.Revision, v
+		}
+	}
+
+	held := []Release{}
+	for _, r := range candidates {
+		if v, _ := r.Revision.Version(); v.Compare(bestVersion) > 0 {
+			held = append(held, r)
+		}
+	}
+	sort.Slice(held, func(i, j int) bool {
+		return module.CompareRevisions(held[i].Revision, held[j].Revision) < 0
+	})
+
+	return best, held, nil
+}
+
+func (l *Latest) heldBack(held []Release) string {
+	revisions := make([]string, 0, len(held))
+	for _, r := range held {
+		revisions = append(revisions, string(r.Revision))
+	}
+
+	return fmt.Sprintf("held back %s released less than %s ago", strings.Join(revisions, ", "), formatAge(l.minAge))
+}
+
+// formatAge renders whole days as "7d", other durations as time.Duration does
+func formatAge(d time.Duration) string {
+	day := 24 * time.Hour
+	if d >= day && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+
+	return d.String()
+}
+
+// NewLatestUpdater creates strategy updating sources to the newest release listed by releases
+// which is at least minAge old, zero minAge takes all releases into account
+func NewLatestUpdater(releases ReleaseLister, minAge time.Duration) *Latest {
+	return &Latest{releases: releases, minAge: minAge, now: time.Now}
+}
@@ -0,0 +1,124 @@
+package strategies
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+// staticReleases lists the same releases for every source
+type staticReleases []Release
+
+func (r staticReleases) Releases(module.Source) ([]Release, error) {
+	if r == nil {
+		return nil, errors.New("repository is not found")
+	}
+
+	return r, nil
+}
+
+func TestLatest(t *testing.T) {
+	now := time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	releases := staticReleases{
+		{Revision: "v1.0.0", Date: now.Add(-100 * day)},
+		{Revision: "v1.1.0", Date: now.Add(-30 * day)},
+		{Revision: "v1.2.0-rc.1", Date: now.Add(-20 * day)},
+		{Revision: "v1.2.0", Date: now.Add(-10 * day)},
+		{Revision: "v1.3.0", Date: now.Add(-2 * day)},
+		{Revision: "v2.0.0", Date: now.Add(-1 * time.Hour)},
+		{Revision: "main", Date: now},
+	}
+
+	testCases := []struct {
+		name               string
+		releases           staticReleases
+		minAge             time.Duration
+		revision           module.Revision
+		expectedRevision   module.Revision
+		expectedExplain    []string
+		expectedSkipReason string
+		expectedError      string
+	}{
+		{
+			name:             "newest release without minimal age",
+			releases:         releases,
+			revision:         "v1.0.0",
+			expectedRevision: "v2.0.0",
+		},
+		{
+			name:             "newer releases are held back",
+			releases:         releases,
+			minAge:           7 * day,
+			revision:         "v1.0.0",
+			expectedRevision: "v1.2.0",
+			expectedExplain:  []string{"held back v1.3.0, v2.0.0 released less than 7d ago"},
+		},
+		{
+			name:               "all newer releases are too young",
+			releases:           releases,
+			minAge:             7 * day,
+			revision:           "v1.2.0",
+			expectedRevision:   "v1.2.0",
+			expectedSkipReason: "held back v1.3.0, v2.0.0 released less than 7d ago",
+		},
+		{
+			name:             "already the newest",
+			releases:         releases,
+			minAge:           time.Hour,
+			revision:         "v2.0.0",
+			expectedRevision: "v2.0.0",
+		},
+		{
+			name:             "releases cannot be listed",
+			revision:         "v1.0.0",
+			expectedRevision: "v1.0.0",
+			expectedError:    "cannot list releases: repository is not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			strategy := NewLatestUpdater(tc.releases, tc.minAge).WithCondition(conditions.HostMatches("github.com"))
+			strategy.now = func() time.Time { return now }
+			source := module.Source{Scheme: "https", Host: "github.com", Module: "/example-org/modules.git", Revision: tc.revision}
+
+			assert.Equal(true, strategy.Decide(source))
+			assert.Equal(tc.expectedRevision, strategy.Apply(source).Revision)
+			assert.Equal(tc.expectedExplain, strategy.Explain(source))
+			reason, err := strategy.SkipReason(source)
+			assert.Equal(tc.expectedSkipReason, reason)
+			if tc.expectedError != "" {
+				assert.Equal(tc.expectedError, err.Error())
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestLatestDecide(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	source := module.Source{Scheme: "https", Host: "github.com", Module: "/example-org/modules.git", Revision: "v1.0.0"}
+
+	assert.Equal(false, NewLatestUpdater(staticReleases{}, 0).Decide(source))
+
+	strategy := NewLatestUpdater(staticReleases{}, 0).WithCondition(conditions.HostMatches("github.com"))
+	assert.Equal(true, strategy.Decide(source))
+
+	// branches and commits are not versions to compare releases with
+	source.Revision = "main"
+	assert.Equal(false, strategy.Decide(source))
+}
+
+func TestFormatAge(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	assert.Equal("7d", formatAge(7*24*time.Hour))
+	assert.Equal("36h0m0s", formatAge(36*time.Hour))
+	assert.Equal("0s", formatAge(0))
+}
@@ -0,0 +1,46 @@
+package strategies
+
+import (
+	"time"
+
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+// Strategy is a type to make decision and mutate module source string
+type Strategy interface {
+	Decide(module.Source) bool
+	Apply(module.Source) module.Source
+}
+
+// FileStrategy is implemented by strategies which decisions depend on the file being processed
+type FileStrategy interface {
+	Strategy
+
+	// ForFile returns strategy to use for module sources of the given file
+	ForFile(path string) Strategy
+}
+
+// Explainer is implemented by strategies which can tell why the source is changed
+type Explainer interface {
+	// Explain returns human readable reasons of changes Apply makes to the source
+	Explain(module.Source) []string
+}
+
+// Skipper is implemented by strategies which might leave the source as is for a reason worth reporting,
+// e.g. when newer revisions exist but are not eligible yet
+type Skipper interface {
+	// SkipReason returns why Apply did not change the source, empty if there is nothing to report.
+	// Error is returned when it cannot be found out if the source should be changed, e.g. releases cannot be listed
+	SkipReason(module.Source) (string, error)
+}
+
+// Release is a released revision of a module, usually a tag
+type Release struct {
+	Revision module.Revision
+	Date     time.Time
+}
+
+// ReleaseLister lists released revisions of the module repository
+type ReleaseLister interface {
+	Releases(module.Source) ([]Release, error)
+}
@@ -1,8 +1,8 @@
 package strategies
 
 import (
-	"github.com/maxim-nazarenko/tf-module-update/internal/conditions"
-	"github.com/maxim-nazarenko/tf-module-update/internal/module"
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/module"
 )
 
 // MutatorFunc is type to change module source
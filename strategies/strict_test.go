@@ -3,9 +3,9 @@ package strategies
 import (
 	"testing"
 
-	"github.com/maxim-nazarenko/tf-module-update/internal/conditions"
-	"github.com/maxim-nazarenko/tf-module-update/internal/module"
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
 	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
 )
 
 func TestStrictDecide(t *testing.T) {
@@ -0,0 +1,105 @@
+package updater
+
+import (
+	"github.com/maxim-nazarenko/tf-module-update/internal/processing"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+// approverAdapter asks public approver to approve changes of the manager
+type approverAdapter struct {
+	approver Approver
+}
+
+func (a approverAdapter) Approve(c processing.Change) bool {
+	return a.approver.Approve(publicChange(c))
+}
+
+// changelogAdapter collects changelogs for the manager with public provider
+type changelogAdapter struct {
+	provider ChangelogProvider
+}
+
+func (a changelogAdapter) Changelog(before, after module.Source) (processing.Changelog, error) {
+	changelog, err := a.provider.Changelog(before, after)
+
+	return processing.Changelog{Commits: changelog.Commits, Notes: changelog.Notes}, err
+}
+
+// interfaceCheckerAdapter checks interface of changes of the manager with public checker
+type interfaceCheckerAdapter struct {
+	checker InterfaceChecker
+}
+
+func (a interfaceCheckerAdapter) CheckInterface(c processing.Change) ([]processing.InterfaceFinding, error) {
+	findings, err := a.checker.CheckInterface(publicChange(c))
+
+	return internalFindings(findings), err
+}
+
+// interactiveApprover exposes approver of the manager as public one
+type interactiveApprover struct {
+	approver *processing.InteractiveApprover
+}
+
+func (a interactiveApprover) Approve(c Change) bool {
+	return a.approver.Approve(internalChange(c))
+}
+
+func publicCall(c processing.ModuleCall) ModuleCall {
+	return ModuleCall{
+		File:             c.File,
+		Name:             c.Name,
+		Block:            c.Block,
+		Source:           c.Source,
+		Version:          c.Version,
+		Arguments:        c.Arguments,
+		ArgumentsUnknown: c.ArgumentsUnknown,
+	}
+}
+
+func internalCall(c ModuleCall) processing.ModuleCall {
+	return processing.ModuleCall{
+		File:             c.File,
+		Name:             c.Name,
+		Block:            c.Block,
+		Source:           c.Source,
+		Version:          c.Version,
+		Arguments:        c.Arguments,
+		ArgumentsUnknown: c.ArgumentsUnknown,
+	}
+}
+
+func publicChange(c processing.Change) Change {
+	change := Change{
+		Call:        publicCall(c.Call),
+		Before:      c.Before,
+		After:       c.After,
+		Explanation: c.Explanation,
+		Changelog:   Changelog{Commits: c.Changelog.Commits, Notes: c.Changelog.Notes},
+	}
+	for _, f := range c.Interface {
+		change.Interface = append(change.Interface, InterfaceFinding(f))
+	}
+
+	return change
+}
+
+func internalChange(c Change) processing.Change {
+	return processing.Change{
+		Call:        internalCall(c.Call),
+		Before:      c.Before,
+		After:       c.After,
+		Explanation: c.Explanation,
+		Changelog:   processing.Changelog{Commits: c.Changelog.Commits, Notes: c.Changelog.Notes},
+		Interface:   internalFindings(c.Interface),
+	}
+}
+
+func internalFindings(findings []InterfaceFinding) []processing.InterfaceFinding {
+	var result []processing.InterfaceFinding
+	for _, f := range findings {
+		result = append(result, processing.InterfaceFinding(f))
+	}
+
+	return result
+}
@@ -0,0 +1,18 @@
+// Package updater is the public API to find and update Terraform module sources.
+//
+// It walks Terraform, OpenTofu, Terraform JSON and optionally Terragrunt files, runs the given strategy
+// against every module source and reports what was found and changed:
+//
+//	strategy := strategies.NewStrictUpdater(func(s module.Source) module.Source {
+//		return s.Merge(module.Source{Revision: "v1.1.0"})
+//	}).WithCondition(conditions.RevisionMatches("v1.0.0"))
+//
+//	report, err := updater.Update(ctx, []string{"./infra"}, strategy, updater.Options{Write: true})
+//
+// Sources are described by package module, strategies and conditions to select and change them
+// live in packages strategies and conditions.
+//
+// These packages follow semantic versioning of the Go module: exported identifiers are not removed
+// or changed in incompatible way within the same major version. Everything under internal
+// is not part of the API.
+package updater
@@ -0,0 +1,246 @@
+package updater
+
+import (
+	"io/fs"
+	"path/filepath"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/processing"
+	"github.com/maxim-nazarenko/tf-module-update/internal/processing/logging"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+)
+
+// ModuleCall describes a single block with module source found in a file, e.g. "module" block
+type ModuleCall struct {
+	// File is the path of the file the call is defined in
+	File string
+
+	// Name is the label of module block, empty for blocks without labels like Terragrunt "terraform"
+	Name string
+
+	// Block is the path of block types to the call, e.g. "module" or "run.module"
+	Block string
+
+	// Source is the module source as it is written in the file after processing
+	Source string
+
+	// Version is the version constraint of registry module, if set
+	Version string
+
+	// Arguments holds sorted names of input variables set by the call: arguments of "module" block
+	// without meta-arguments, or keys of "inputs" of the Terragrunt file for "terraform" block
+	Arguments []string
+
+	// ArgumentsUnknown reports that Arguments might miss input variables set by the call,
+	// e.g. Terragrunt inputs merged from included files or built with functions
+	ArgumentsUnknown bool
+}
+
+// Dir returns directory of the file with module call
+func (c ModuleCall) Dir() string {
+	return filepath.Dir(c.File)
+}
+
+// Change describes module source updated in a module call
+type Change struct {
+	// Call is the module call as it was before the change
+	Call   ModuleCall
+	Before module.Source
+	After  module.Source
+
+	// Explanation holds reasons given by the strategy for the new source, if any
+	Explanation []string
+
+	// Changelog holds changes of the module between the revisions, if collected
+	Changelog Changelog
+
+	// Interface holds differences of the module interface between the revisions, if checked
+	Interface []InterfaceFinding
+}
+
+// Skip describes module call which was not updated, e.g. due to inline directive
+type Skip struct {
+	Call   ModuleCall
+	Reason string
+}
+
+// Written describes a file written during processing
+type Written struct {
+	File string
+
+	// Backup is the path of the file with original content, empty if backup was not requested
+	Backup string
+
+	// SHA256 is hex encoded hash of the written content
+	SHA256 string
+}
+
+// FileWriter writes updated files back
+type FileWriter interface {
+	WriteFile(name string, data []byte) error
+}
+
+// BackupWriter is implemented by writers which write backups differently from other files,
+// backups are written with WriteFile of writers which do not implement it
+type BackupWriter interface {
+	// WriteBackup writes copy of the original file with the given name
+	WriteBackup(name string, original string, data []byte) error
+}
+
+// Approver decides if the change should be applied, see NewInteractiveApprover. The change already
+// holds its explanation, changelog and interface findings when Changelogs and InterfaceChecker are set
+//
+// Approve is never called concurrently, files are processed one by one when approver is set
+type Approver interface {
+	Approve(Change) bool
+}
+
+// Validator checks that the new module source exists before it is written
+//
+// Validate might be called concurrently when files are processed by several jobs
+type Validator interface {
+	Validate(module.Source) error
+}
+
+// Changelog describes what changed in the module between the old and the new revision
+type Changelog struct {
+	// Commits holds subjects of commits touching the module, the newest first
+	Commits []string
+
+	// Notes holds changelog file sections of revisions after the old one up to the new one
+	Notes []string
+}
+
+// IsEmpty reports if nothing is known about the changes
+func (c Changelog) IsEmpty() bool {
+	return len(c.Commits) == 0 && len(c.Notes) == 0
+}
+
+// ChangelogProvider collects changes made in the module between revisions of the sources
+//
+// Changelog might be called concurrently when files are processed by several jobs
+type ChangelogProvider interface {
+	Changelog(before, after module.Source) (Changelog, error)
+}
+
+// InterfaceFinding is a difference of module interface between the old and the new revision
+type InterfaceFinding struct {
+	// Kind is the kind of difference, e.g. "removed-variable"
+	Kind string
+
+	// Name is the name of the variable or output
+	Name string
+
+	// Breaking reports that the call stops working with the new revision, e.g. it sets a removed variable
+	Breaking bool
+
+	// Message describes the difference for humans
+	Message string
+}
+
+// InterfaceChecker compares module interface at revisions of the change
+//
+// CheckInterface might be called concurrently when files are processed by several jobs
+type InterfaceChecker interface {
+	CheckInterface(Change) ([]InterfaceFinding, error)
+}
+
+// DefaultIgnoreFiles contains names of ignore files used by the command line tool
+var DefaultIgnoreFiles = append([]string{}, processing.DefaultIgnoreFiles...)
+
+// BackupSuffix is appended to the file name to get the name of its backup
+const BackupSuffix = processing.BackupSuffix
+
+// Level is a level of report messages
+type Level int
+
+const (
+	LevelTrace = Level(logging.TRACE)
+	LevelDebug = Level(logging.DEBUG)
+	LevelInfo  = Level(logging.INFO)
+	LevelWarn  = Level(logging.WARN)
+	LevelError = Level(logging.ERROR)
+)
+
+func (l Level) String() string {
+	return logging.Level(l).String()
+}
+
+// Message is a human-readable report message
+type Message struct {
+	Level Level
+	Text  string
+}
+
+// Options control which files are processed and how
+type Options struct {
+	// Write makes Update write changed files, otherwise changes are only reported
+	Write bool
+
+	// Terragrunt enables processing of "terraform { source = ... }" blocks in *.hcl files
+	Terragrunt bool
+
+	// Jobs is the number of files processed concurrently, values below 1 mean sequential processing
+	Jobs int
+
+	// ExcludeNames excludes files and folders with these names
+	ExcludeNames []string
+
+	// IncludeHidden disables default exclusion of files and folders starting with dot
+	IncludeHidden bool
+
+	// Include, if not empty, limits processing to files matching any of these globs,
+	// globs are matched against paths relative to the walked folder, "**" matches any number of folders
+	Include []string
+
+	// Exclude skips files and folders matching any of these globs, see Include
+	Exclude []string
+
+	// IgnoreFiles holds names of files in .gitignore format honoured in every walked folder, see DefaultIgnoreFiles
+	IgnoreFiles []string
+
+	// MaxDepth limits how deep folders are walked, 1 means only files of the given folders, 0 means no limit
+	MaxDepth int
+
+	// FS is used to read files, the local file system is used when it is nil
+	FS fs.FS
+
+	// Writer is used to write files, files are written to the local file system when both Writer and FS are nil.
+	// Writing fails when only FS is given, so files of other file systems never end up on the local disk
+	Writer FileWriter
+
+	// Approver, if set, is asked to approve every change, files are processed sequentially then
+	Approver Approver
+
+	// Validator, if set, checks every new source, the block is left untouched and an error is reported if it fails
+	Validator Validator
+
+	// Changelogs, if set, collects changes of the module for every change, see Change.Changelog
+	Changelogs ChangelogProvider
+
+	// InterfaceChecker, if set, compares module interface at revisions of every change, see Change.Interface
+	InterfaceChecker InterfaceChecker
+
+	// Backup makes Update keep original content of every written file next to it with ".bak" suffix
+	Backup bool
+}
+
+// Report holds everything found during processing
+type Report struct {
+	// Calls holds all found module calls with sources after update
+	Calls []ModuleCall
+
+	// Changes holds updated module sources
+	Changes []Change
+
+	// Skips holds module sources which were not updated although the strategy asked to
+	Skips []Skip
+
+	// Written holds files written by Update
+	Written []Written
+
+	// Messages holds human-readable messages of all levels
+	Messages []Message
+
+	// Errors holds errors of particular files, processing continues with other files after them
+	Errors []error
+}
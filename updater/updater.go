@@ -0,0 +1,136 @@
+package updater
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/maxim-nazarenko/tf-module-update/internal/processing"
+	"github.com/maxim-nazarenko/tf-module-update/internal/processing/logging"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+)
+
+// Update runs the strategy against module sources in the given paths, directories are walked recursively
+//
+// Errors of particular files are collected in the report, the returned error is not nil
+// only if ctx is done before all files are processed
+func Update(ctx context.Context, paths []string, strategy strategies.Strategy, opts Options) (*Report, error) {
+	results := processing.NewResults(logging.TRACE)
+	processing.NewManager(opts.managerConfig(), strategy).ProcessPathsContext(ctx, paths, results)
+
+	return newReport(results), ctx.Err()
+}
+
+// List returns module calls found in the given paths without updating anything
+func List(ctx context.Context, paths []string, opts Options) (*Report, error) {
+	opts.Write = false
+	opts.Approver = nil
+
+	return Update(ctx, paths, strategies.NewStrictUpdater(nil), opts)
+}
+
+// UpdateSource runs the strategy against module sources of a single file read from r
+// and writes the updated content to w
+//
+// The path is used only to pick the syntax and in messages. The original content is written
+// when it cannot be processed, its error is collected in the report
+func UpdateSource(ctx context.Context, r io.Reader, w io.Writer, path string, strategy strategies.Strategy, opts Options) (*Report, error) {
+	if err := ctx.Err(); err != nil {
+		return &Report{}, err
+	}
+
+	results := processing.NewResults(logging.TRACE)
+	processing.NewManager(opts.managerConfig(), strategy).ProcessSource(r, w, path, results)
+
+	return newReport(results), nil
+}
+
+// HasErrors reports if any file failed to be processed
+func (r *Report) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// Log renders messages of the given level and above followed by errors, one per line
+func (r *Report) Log(level Level) string {
+	lines := []string{}
+	for _, m := range r.Messages {
+		if m.Level >= level {
+			lines = append(lines, m.Text)
+		}
+	}
+	for _, err := range r.Errors {
+		lines = append(lines, err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// NewInteractiveApprover returns approver which asks to approve each change via out and reads answers from in
+func NewInteractiveApprover(in io.Reader, out io.Writer) Approver {
+	return interactiveApprover{approver: processing.NewInteractiveApprover(in, out)}
+}
+
+// LevelFromString converts one of trace, debug, info, warn and error to Level
+func LevelFromString(level string) (Level, error) {
+	l, err := processing.LevelFromString(level)
+
+	return Level(l), err
+}
+
+func (o Options) managerConfig() processing.Config {
+	config := processing.Config{
+		Write:        o.Write,
+		ExcludeNames: o.ExcludeNames,
+		Terragrunt:   o.Terragrunt,
+		Jobs:         o.Jobs,
+		Include:      o.Include,
+		Exclude:      o.Exclude,
+		IgnoreFiles:  o.IgnoreFiles,
+		MaxDepth:     o.MaxDepth,
+		Validator:    o.Validator,
+		FS:           o.FS,
+		Writer:       o.Writer,
+		Backup:       o.Backup,
+	}
+	if o.Approver != nil {
+		config.Approver = approverAdapter{approver: o.Approver}
+	}
+	if o.Changelogs != nil {
+		config.Changelogs = changelogAdapter{provider: o.Changelogs}
+	}
+	if o.InterfaceChecker != nil {
+		config.InterfaceChecker = interfaceCheckerAdapter{checker: o.InterfaceChecker}
+	}
+	if !o.IncludeHidden {
+		config.ExcludeItemsFunc = processing.DefaultExclusionFunc
+	}
+
+	return config
+}
+
+func newReport(results *processing.Results) *Report {
+	report := &Report{
+		Calls:   []ModuleCall{},
+		Changes: []Change{},
+		Skips:   []Skip{},
+		Written: []Written{},
+		Errors:  results.Errors(),
+	}
+	for _, c := range results.ModuleCalls() {
+		report.Calls = append(report.Calls, publicCall(c))
+	}
+	for _, c := range results.Changes() {
+		report.Changes = append(report.Changes, publicChange(c))
+	}
+	for _, s := range results.Skips() {
+		report.Skips = append(report.Skips, Skip{Call: publicCall(s.Call), Reason: s.Reason})
+	}
+	for _, w := range results.Written() {
+		report.Written = append(report.Written, Written(w))
+	}
+	for _, m := range results.Messages() {
+		report.Messages = append(report.Messages, Message{Level: Level(m.Level), Text: m.Message})
+	}
+
+	return report
+}
@@ -0,0 +1,173 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/maxim-nazarenko/tf-module-update/conditions"
+	"github.com/maxim-nazarenko/tf-module-update/internal/testhelpers"
+	"github.com/maxim-nazarenko/tf-module-update/module"
+	"github.com/maxim-nazarenko/tf-module-update/strategies"
+)
+
+// mapWriter records written files in memory
+type mapWriter map[string]string
+
+func (w mapWriter) WriteFile(name string, data []byte) error {
+	w[name] = string(data)
+	return nil
+}
+
+var testStrategy = strategies.NewStrictUpdater(func(s module.Source) module.Source {
+	return s.Merge(module.Source{Revision: module.Revision("v1.1.0")})
+}).WithCondition(conditions.RevisionMatches(module.Revision("v1.0.0")))
+
+var testFS = fstest.MapFS{
+	"stack/main.tf": {Data: []byte(`module "vpc" {
+  source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0"
+}
+module "db" {
+  source = "git::https://github.com/example-org/modules.git//db?ref=v2.0.0"
+}
+`)},
+	"stack/.hidden/main.tf": {Data: []byte(`module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }`)},
+	"broken/main.tf":        {Data: []byte(`module "vpc" {`)},
+}
+
+func TestUpdate(t *testing.T) {
+	testCases := []struct {
+		name            string
+		paths           []string
+		opts            Options
+		expectedChanges int
+		expectedCalls   int
+		expectedErrors  int
+		expectedWritten []string
+	}{
+		{
+			name:            "write changes",
+			paths:           []string{"stack"},
+			opts:            Options{Write: true},
+			expectedChanges: 1,
+			expectedCalls:   2,
+			expectedWritten: []string{"stack/main.tf"},
+		},
+		{
+			name:            "include hidden without writing",
+			paths:           []string{"stack"},
+			opts:            Options{IncludeHidden: true},
+			expectedChanges: 2,
+			expectedCalls:   3,
+			expectedWritten: []string{},
+		},
+		{
+			name:            "errors are collected",
+			paths:           []string{"."},
+			opts:            Options{Write: true},
+			expectedChanges: 1,
+			expectedCalls:   2,
+			expectedErrors:  1,
+			expectedWritten: []string{"stack/main.tf"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testhelpers.Assert(t)
+			written := mapWriter{}
+			tc.opts.FS = testFS
+			tc.opts.Writer = written
+
+			report, err := Update(context.Background(), tc.paths, testStrategy, tc.opts)
+			assert.NoError(err)
+
+			files := []string{}
+			for f := range written {
+				files = append(files, f)
+			}
+			assert.Equal(tc.expectedChanges, len(report.Changes))
+			assert.Equal(tc.expectedCalls, len(report.Calls))
+			assert.Equal(tc.expectedErrors, len(report.Errors))
+			assert.Equal(tc.expectedWritten, files)
+		})
+	}
+}
+
+func TestUpdateCancelled(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := Update(ctx, []string{"stack"}, testStrategy, Options{FS: testFS, Writer: mapWriter{}})
+	assert.Equal(context.Canceled, err)
+	assert.Equal(0, len(report.Changes))
+}
+
+func TestList(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	written := mapWriter{}
+
+	report, err := List(context.Background(), []string{"stack"}, Options{Write: true, FS: testFS, Writer: written})
+	assert.NoError(err)
+	assert.Equal(2, len(report.Calls))
+	assert.Equal(0, len(report.Changes))
+	assert.Equal(mapWriter{}, written)
+}
+
+func TestUpdateSource(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	out := &bytes.Buffer{}
+
+	report, err := UpdateSource(
+		context.Background(),
+		strings.NewReader(`module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0" }`),
+		out, "main.tf", testStrategy, Options{},
+	)
+	assert.NoError(err)
+	assert.Equal(`module "vpc" { source = "git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0" }`, out.String())
+	assert.Equal(`In file main.tf:
+  - git::https://github.com/example-org/modules.git//vpc?ref=v1.0.0
+  + git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0`, report.Log(LevelInfo))
+}
+
+// testHooks approves every change and reports fixed changelog and interface findings
+type testHooks struct {
+	approved []string
+}
+
+func (h *testHooks) Approve(c Change) bool {
+	h.approved = append(h.approved, c.Call.Name+": "+c.After.String())
+	return true
+}
+
+func (h *testHooks) Changelog(before, after module.Source) (Changelog, error) {
+	return Changelog{Commits: []string{"Add " + string(after.Revision)}}, nil
+}
+
+func (h *testHooks) CheckInterface(c Change) ([]InterfaceFinding, error) {
+	return []InterfaceFinding{{Kind: "added-variable", Name: "zone", Message: "variable \"zone\" is added, changelog: " + c.Changelog.Commits[0]}}, nil
+}
+
+func TestUpdateHooks(t *testing.T) {
+	assert := testhelpers.Assert(t)
+	hooks := &testHooks{}
+
+	report, err := Update(context.Background(), []string{"stack"}, testStrategy, Options{
+		FS:               testFS,
+		Approver:         hooks,
+		Changelogs:       hooks,
+		InterfaceChecker: hooks,
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"vpc: git::https://github.com/example-org/modules.git//vpc?ref=v1.1.0"}, hooks.approved)
+	assert.Equal(1, len(report.Changes))
+	assert.Equal(Changelog{Commits: []string{"Add v1.1.0"}}, report.Changes[0].Changelog)
+	assert.Equal([]InterfaceFinding{{
+		Kind:    "added-variable",
+		Name:    "zone",
+		Message: "variable \"zone\" is added, changelog: Add v1.1.0",
+	}}, report.Changes[0].Interface)
+}